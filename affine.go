@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"fmt"
+	"math"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// affine transforms
+
+// Affine is a 2D affine transform. It maps (x, y) to
+//
+//	x' = A*x + B*y + C
+//	y' = D*x + E*y + F
+//
+// Transforms are built up in the order they are applied, so
+// Identity().Scale(2, 2).Translate(10, 0) doubles a point and then moves it right.
+type Affine struct {
+	A, B, C float64
+	D, E, F float64
+}
+
+// Identity returns the transform that leaves points where they are.
+func Identity() Affine {
+	return Affine{A: 1, E: 1}
+}
+
+// NewTranslation returns a transform that moves points by dx and dy.
+func NewTranslation(dx, dy float64) Affine {
+	return Affine{A: 1, C: dx, E: 1, F: dy}
+}
+
+// NewScale returns a transform that scales points away from the origin.
+func NewScale(sx, sy float64) Affine {
+	return Affine{A: sx, E: sy}
+}
+
+// NewRotation returns a transform that rotates points around the origin by the angle, in radians.
+// Positive angles are clockwise on the screen.
+func NewRotation(radians float64) Affine {
+	sin, cos := math.Sincos(radians)
+	return Affine{A: cos, B: -sin, D: sin, E: cos}
+}
+
+func (a Affine) String() string {
+	return fmt.Sprintf("[%f %f %f; %f %f %f]", a.A, a.B, a.C, a.D, a.E, a.F)
+}
+
+// Then returns the transform that applies a and then b.
+func (a Affine) Then(b Affine) Affine {
+	return Affine{
+		A: b.A*a.A + b.B*a.D,
+		B: b.A*a.B + b.B*a.E,
+		C: b.A*a.C + b.B*a.F + b.C,
+		D: b.D*a.A + b.E*a.D,
+		E: b.D*a.B + b.E*a.E,
+		F: b.D*a.C + b.E*a.F + b.F,
+	}
+}
+
+// Translate returns a followed by a move of dx and dy.
+func (a Affine) Translate(dx, dy float64) Affine {
+	return a.Then(NewTranslation(dx, dy))
+}
+
+// Scale returns a followed by a scale away from the origin.
+func (a Affine) Scale(sx, sy float64) Affine {
+	return a.Then(NewScale(sx, sy))
+}
+
+// Rotate returns a followed by a rotation around the origin.
+func (a Affine) Rotate(radians float64) Affine {
+	return a.Then(NewRotation(radians))
+}
+
+// RotateAround returns a followed by a rotation around the center.
+func (a Affine) RotateAround(center Point, radians float64) Affine {
+	return a.Translate(-center.X, -center.Y).Rotate(radians).Translate(center.X, center.Y)
+}
+
+// Determinant returns the determinant of the linear part of the transform.
+// It is zero when the transform collapses the plane onto a line or a point.
+func (a Affine) Determinant() float64 {
+	return a.A*a.E - a.B*a.D
+}
+
+// Invert returns the transform that undoes a.
+// It returns false if a can't be undone because its determinant is zero.
+func (a Affine) Invert() (Affine, bool) {
+	det := a.Determinant()
+	if det == 0 {
+		return Affine{}, false
+	}
+	return Affine{
+		A: a.E / det,
+		B: -a.B / det,
+		C: (a.B*a.F - a.E*a.C) / det,
+		D: -a.D / det,
+		E: a.A / det,
+		F: (a.D*a.C - a.A*a.F) / det,
+	}, true
+}
+
+// Apply returns the point moved by the transform.
+// The explicit float64 conversions keep the results the same on every platform,
+// see hex_to_pixel. They also keep the identity transform exact.
+func (a Affine) Apply(p Point) Point {
+	return Point{
+		X: float64(a.A*p.X) + float64(a.B*p.Y) + a.C,
+		Y: float64(a.D*p.X) + float64(a.E*p.Y) + a.F,
+	}
+}
+
+// ApplyVector returns the vector moved by the linear part of the transform,
+// ignoring the translation.
+func (a Affine) ApplyVector(p Point) Point {
+	return Point{
+		X: float64(a.A*p.X) + float64(a.B*p.Y),
+		Y: float64(a.D*p.X) + float64(a.E*p.Y),
+	}
+}
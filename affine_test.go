@@ -0,0 +1,125 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"math"
+	"testing"
+)
+
+// affine_near returns true if the coefficients of the transforms are within a billionth of each other.
+func affine_near(a, b Affine) bool {
+	for _, d := range []float64{a.A - b.A, a.B - b.B, a.C - b.C, a.D - b.D, a.E - b.E, a.F - b.F} {
+		if math.Abs(d) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+// test_transforms returns transforms that are built from every kind of step.
+func test_transforms() map[string]Affine {
+	return map[string]Affine{
+		"identity":  Identity(),
+		"translate": NewTranslation(12, -7),
+		"scale":     NewScale(2, 0.5),
+		"rotate":    NewRotation(0.7),
+		"mirror":    NewScale(-1, 1),
+		"camera":    Identity().Scale(1.3, 0.8).RotateAround(NewPoint(20, 30), 0.45).Translate(7, -3),
+		"shear":     {A: 1, B: 0.75, C: 3, D: -0.25, E: 2, F: -4},
+	}
+}
+
+func TestAffineApply(t *testing.T) {
+	p := NewPoint(3, -2)
+	for _, tc := range []struct {
+		id   int
+		a    Affine
+		want Point
+	}{
+		{1, Identity(), p},
+		{2, NewTranslation(10, 5), NewPoint(13, 3)},
+		{3, NewScale(2, -3), NewPoint(6, 6)},
+		{4, NewRotation(math.Pi / 2), NewPoint(2, 3)},
+		{5, Identity().Scale(2, 2).Translate(10, 0), NewPoint(16, -4)},
+		{6, Identity().Translate(10, 0).Scale(2, 2), NewPoint(26, -4)},
+		{7, Identity().RotateAround(NewPoint(3, 0), math.Pi), NewPoint(3, 2)},
+		{8, Affine{A: 1, B: 2, C: 3, D: 4, E: 5, F: 6}, NewPoint(2, 8)},
+	} {
+		if got := tc.a.Apply(p); !points_near(got, tc.want) {
+			t.Errorf("%d: %v.Apply(%v): want %v: got %v", tc.id, tc.a, p, tc.want, got)
+		}
+		// a vector is moved by the linear part only.
+		if got, want := tc.a.ApplyVector(p), tc.a.Apply(p).Sub(tc.a.Apply(NewPoint(0, 0))); !points_near(got, want) {
+			t.Errorf("%d: %v.ApplyVector(%v): want %v: got %v", tc.id, tc.a, p, want, got)
+		}
+	}
+	// the identity is exact.
+	if p := NewPoint(0.1, 1e300); Identity().Apply(p) != p {
+		t.Errorf("Identity: want %v unchanged: got %v", p, Identity().Apply(p))
+	}
+}
+
+func TestAffineThen(t *testing.T) {
+	points := []Point{NewPoint(0, 0), NewPoint(1, 0), NewPoint(-3, 7.5), NewPoint(100, -40)}
+	for an, a := range test_transforms() {
+		for bn, b := range test_transforms() {
+			ab := a.Then(b)
+			for _, p := range points {
+				if got, want := ab.Apply(p), b.Apply(a.Apply(p)); !points_near(got, want) {
+					t.Errorf("%s.Then(%s).Apply(%v): want %v: got %v", an, bn, p, want, got)
+				}
+			}
+			if math.Abs(ab.Determinant()-a.Determinant()*b.Determinant()) > 1e-9 {
+				t.Errorf("%s.Then(%s): want determinant %v: got %v", an, bn, a.Determinant()*b.Determinant(), ab.Determinant())
+			}
+		}
+		if !affine_near(a.Then(Identity()), a) || !affine_near(Identity().Then(a), a) {
+			t.Errorf("%s: want Then(Identity()) to leave it alone", an)
+		}
+	}
+}
+
+func TestAffineInvert(t *testing.T) {
+	for name, a := range test_transforms() {
+		inverse, ok := a.Invert()
+		if !ok {
+			t.Errorf("%s: Invert: want ok", name)
+			continue
+		}
+		if got := a.Then(inverse); !affine_near(got, Identity()) {
+			t.Errorf("%s: a.Then(a.Invert()): want the identity: got %v", name, got)
+		}
+		if got := inverse.Then(a); !affine_near(got, Identity()) {
+			t.Errorf("%s: a.Invert().Then(a): want the identity: got %v", name, got)
+		}
+		for _, p := range []Point{NewPoint(0, 0), NewPoint(5, -9), NewPoint(-31.5, 2)} {
+			if got := inverse.Apply(a.Apply(p)); !points_near(got, p) {
+				t.Errorf("%s: Invert: want %v back: got %v", name, p, got)
+			}
+		}
+	}
+	for _, a := range []Affine{NewScale(0, 1), NewScale(0, 0), {A: 1, B: 2, D: 2, E: 4, C: 5}} {
+		if _, ok := a.Invert(); ok {
+			t.Errorf("%v: Invert: want false for a singular transform", a)
+		}
+	}
+}
+
+func TestAffineRotateAround(t *testing.T) {
+	center := NewPoint(20, 30)
+	for _, radians := range []float64{0, 0.45, math.Pi / 2, math.Pi, -2.1} {
+		a := Identity().RotateAround(center, radians)
+		if got := a.Apply(center); !points_near(got, center) {
+			t.Errorf("%v: RotateAround: want the center fixed: got %v", radians, got)
+		}
+		for _, p := range []Point{NewPoint(0, 0), NewPoint(25, 30), NewPoint(-7, 12)} {
+			if got, want := a.Apply(p), p.RotateAround(center, radians); !points_near(got, want) {
+				t.Errorf("%v: RotateAround(%v): want %v: got %v", radians, p, want, got)
+			}
+		}
+		if math.Abs(a.Determinant()-1) > 1e-12 {
+			t.Errorf("%v: RotateAround: want determinant 1: got %v", radians, a.Determinant())
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// camera
+//
+// A camera is an affine transform applied to the screen coordinates of a
+// layout, so the map can be panned, zoomed and rotated without changing the
+// layout. CameraLayout wraps any Layout, so the camera isn't part of the
+// Layout interface. Size and Origin are the values before the camera is applied.
+
+// ErrCamera is returned when a camera can't be inverted, which would make
+// every point on the screen land on the same hex.
+var ErrCamera = errors.New("camera can't be inverted")
+
+// CameraLayout is a layout seen through a camera.
+type CameraLayout struct {
+	layout  Layout
+	camera  Affine
+	inverse Affine // undoes the camera
+}
+
+// NewCameraLayout returns the layout seen through the camera.
+// If the layout already has a camera, the new camera is applied after it.
+// It returns ErrCamera if the camera can't be inverted.
+func NewCameraLayout(layout Layout, camera Affine) (*CameraLayout, error) {
+	if cl, ok := layout.(*CameraLayout); ok {
+		layout, camera = cl.layout, cl.camera.Then(camera)
+	}
+	inverse, ok := camera.Invert()
+	if !ok {
+		return nil, ErrCamera
+	}
+	return &CameraLayout{layout: layout, camera: camera, inverse: inverse}, nil
+}
+
+// WithoutCamera returns the layout under the camera, or the layout itself if it doesn't have one.
+func WithoutCamera(layout Layout) Layout {
+	if cl, ok := layout.(*CameraLayout); ok {
+		return cl.layout
+	}
+	return layout
+}
+
+// split_camera returns the layout under the camera and the camera.
+// Layouts without a camera have the identity.
+func split_camera(layout Layout) (Layout, Affine) {
+	if cl, ok := layout.(*CameraLayout); ok {
+		return cl.layout, cl.camera
+	}
+	return layout, Identity()
+}
+
+// Camera returns the camera transform.
+func (cl *CameraLayout) Camera() Affine {
+	return cl.camera
+}
+
+// Layout returns the layout under the camera.
+func (cl *CameraLayout) Layout() Layout {
+	return cl.layout
+}
+
+func (cl *CameraLayout) Orientation() Orientation {
+	return cl.layout.Orientation()
+}
+
+func (cl *CameraLayout) Parity() Parity {
+	return cl.layout.Parity()
+}
+
+func (cl *CameraLayout) Size() Point {
+	return cl.layout.Size()
+}
+
+func (cl *CameraLayout) Origin() Point {
+	return cl.layout.Origin()
+}
+
+func (cl *CameraLayout) HexToCenterPoint(h Hex) Point {
+	return cl.camera.Apply(cl.layout.HexToCenterPoint(h))
+}
+
+func (cl *CameraLayout) PixelToFractionalHex(p Point) FractionalHex {
+	return cl.layout.PixelToFractionalHex(cl.inverse.Apply(p))
+}
+
+func (cl *CameraLayout) PixelToHex(p Point) Hex {
+	return cl.layout.PixelToHex(cl.inverse.Apply(p))
+}
+
+func (cl *CameraLayout) Points(h Hex) (center Point, corners [6]Point) {
+	center, corners = cl.layout.Points(h)
+	for i, pt := range corners {
+		corners[i] = cl.camera.Apply(pt)
+	}
+	return cl.camera.Apply(center), corners
+}
+
+func (cl *CameraLayout) HexToOffset(h Hex) (column, row int) {
+	return cl.layout.HexToOffset(h)
+}
+
+func (cl *CameraLayout) OffsetToHex(column, row int) Hex {
+	return cl.layout.OffsetToHex(column, row)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCameraLayout(t *testing.T) {
+	if _, err := NewCameraLayout(NewFlatOddLayout(NewPoint(10, 15), NewPoint(35, 71)), NewScale(0, 0)); !errors.Is(err, ErrCamera) {
+		t.Errorf("singular camera: want %v: got %v", ErrCamera, err)
+	}
+
+	camera := Identity().Scale(2, 1.5).RotateAround(NewPoint(30, 40), 0.7).Translate(-12, 9)
+	for name, layout := range test_layouts() {
+		cl, err := NewCameraLayout(layout, camera)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if WithoutCamera(cl) != layout {
+			t.Errorf("%s: WithoutCamera: want the wrapped layout", name)
+		}
+		for _, h := range NewHexagonGrid[bool](6).Hexes() {
+			p := cl.HexToCenterPoint(h)
+			if got := cl.PixelToHex(p); got != h {
+				t.Errorf("%s: PixelToHex(HexToCenterPoint(%v)): got %v", name, h, got)
+			}
+			if want := camera.Apply(layout.HexToCenterPoint(h)); p.Distance(want) > 1e-9 {
+				t.Errorf("%s: %v: want %v: got %v", name, h, want, p)
+			}
+		}
+	}
+
+	// cameras on cameras are composed
+	layout := NewPointyOddLayout(NewPoint(10, 10), NewPoint(0, 0))
+	a, _ := NewCameraLayout(layout, NewScale(2, 2))
+	b, _ := NewCameraLayout(a, NewTranslation(5, 0))
+	if b.Layout() != layout {
+		t.Errorf("nested camera: want the bottom layout")
+	}
+	h := NewHex(1, 2, -3)
+	if got, want := b.HexToCenterPoint(h), layout.HexToCenterPoint(h).Scale(2).Add(NewPoint(5, 0)); got.Distance(want) > 1e-9 {
+		t.Errorf("nested camera: want %v: got %v", want, got)
+	}
+}
+
+func TestTopologyCamera(t *testing.T) {
+	layout := NewFlatEvenLayout(NewPoint(10, 10), NewPoint(0, 0))
+	zoomed, _ := NewCameraLayout(layout, NewScale(2, 2))
+	plain, err := NewTorusTopology(layout, 8, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	camera, err := NewTorusTopology(zoomed, 8, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topLeft, bottomRight := plain.Bounds()
+	gotTopLeft, gotBottomRight := camera.Bounds()
+	if gotTopLeft.Distance(topLeft.Scale(2)) > 1e-9 || gotBottomRight.Distance(bottomRight.Scale(2)) > 1e-9 {
+		t.Errorf("bounds: want %v %v: got %v %v", topLeft.Scale(2), bottomRight.Scale(2), gotTopLeft, gotBottomRight)
+	}
+	for _, h := range plain.Hexes() {
+		if want, got := plain.Images(h), camera.Images(h); len(want) != len(got) {
+			t.Errorf("%v: images: want %v: got %v", h, want, got)
+		}
+	}
+}
@@ -7,39 +7,58 @@ import (
 	"fmt"
 	"github.com/fogleman/gg"
 	"github.com/playbymail/hexes"
+	"github.com/playbymail/hexes/diff"
+	"github.com/playbymail/hexes/fog"
+	"github.com/playbymail/hexes/mapfile"
+	"github.com/playbymail/hexes/raster"
+	"github.com/playbymail/hexes/sphere"
+	"github.com/playbymail/hexes/svg"
+	"github.com/playbymail/hexes/terrain"
+	"image/color"
 	"log"
 	"math"
+	"os"
 )
 
 func main() {
 	cx, cy := 512.0, 512.0
 
 	layout := hexes.NewFlatEvenLayout(hexes.NewPoint(50, 50), hexes.NewPoint(cx, cy))
-	err := drawHexes("flat-even.png", newHexMap(5), layout)
+	err := drawHexes("flat-even.png", hexes.NewHexagonGrid[bool](5).Hexes(), layout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawSVG("flat-even.svg", hexes.NewHexagonGrid[bool](5).Hexes(), layout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = saveMap("flat-even.hexmap", mapfile.Shape{Kind: "hexagon", Radius: 5}, layout)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	layout = hexes.NewPointyEvenLayout(hexes.NewPoint(50, 50), hexes.NewPoint(cx, cy))
-	err = drawHexes("pointy-even.png", newHexMap(5), layout)
+	err = drawHexes("pointy-even.png", hexes.NewHexagonGrid[bool](5).Hexes(), layout)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	layout = hexes.NewFlatEvenLayout(hexes.NewPoint(50, 50), hexes.NewPoint(100, 100))
-	err = drawHexes("flat-even-square.png", newSquareMap(layout, 5), layout)
+	err = drawHexes("flat-even-square.png", hexes.NewRectangleGrid[bool](layout, 6, 6).Hexes(), layout)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	layout = hexes.NewPointyEvenLayout(hexes.NewPoint(50, 50), hexes.NewPoint(100, 100))
-	err = drawHexes("pointy-even-square.png", newSquareMap(layout, 5), layout)
+	err = drawHexes("pointy-even-square.png", hexes.NewRectangleGrid[bool](layout, 6, 6).Hexes(), layout)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	layout = hexes.NewFlatOddLayout(hexes.NewPoint(50, 50), hexes.NewPoint(100, 100))
-	err = drawHexes("flat-odd-square.png", newSquareMap(layout, 5), layout)
+	err = drawHexes("flat-odd-square.png", hexes.NewRectangleGrid[bool](layout, 6, 6).Hexes(), layout)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -49,59 +68,231 @@ func main() {
 		log.Fatal(err)
 	}
 
-	err = drawHexesOnScaledImage("testmap.png", "hex-scaled-map.png")
+	err = drawHexesOnScaledImage("testmap.png", "hex-scaled-map.png", 1.25)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawSphereNet("sphere-net.png", 4)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawTerrain("terrain.png", 42)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawFog("fog.png", 42)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawDiff("diff.png")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawCamera("camera.png")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = drawLasso("lasso.png")
 	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func newHexMap(n int) (h []hexes.Hex) {
-	for q := -n; q <= n; q++ {
-		r1, r2 := max(-n, -q-n), min(n, -q+n)
-		for r := r1; r <= r2; r++ {
-			h = append(h, hexes.NewHex(q, r, -q-r))
-		}
+func drawHexes(path string, hv []hexes.Hex, l hexes.Layout) error {
+	img := raster.Draw(l, hv, raster.Options{
+		Width:        1024,
+		Height:       1024,
+		LineWidth:    2,
+		CenterRadius: 3,
+		Label:        raster.OffsetLabel(l),
+	})
+
+	err := gg.SavePNG(path, img)
+	if err == nil {
+		log.Printf("created %s\n", path)
 	}
-	return h
+
+	return err
 }
 
-func newSquareMap(l hexes.Layout, n int) (h []hexes.Hex) {
-	for column := 0; column <= n; column++ {
-		for row := 0; row <= n; row++ {
-			h = append(h, l.OffsetToHex(column, row))
-		}
+// drawSphereNet draws the unfolded net of a sphere grid with the pentagons in red.
+func drawSphereNet(path string, frequency int) error {
+	g, err := sphere.NewGrid(frequency)
+	if err != nil {
+		return err
+	}
+	net := g.Net()
+	hv := make([]hexes.Hex, 0, len(net))
+	for h := range net {
+		hv = append(hv, h)
 	}
-	return h
+
+	l := hexes.NewPointyOddLayout(hexes.NewPoint(15, 15), hexes.NewPoint(0, 0))
+	img := raster.Draw(l, hv, raster.Options{
+		Margin:    10,
+		LineWidth: 1,
+		Fill: func(h hexes.Hex) color.Color {
+			if g.IsPentagon(net[h]) {
+				return color.RGBA{R: 0xcc, G: 0x33, B: 0x33, A: 0xff}
+			}
+			return color.RGBA{R: 0x99, G: 0xcc, B: 0x99, A: 0xff}
+		},
+	})
+
+	err = gg.SavePNG(path, img)
+	if err == nil {
+		log.Printf("created %s\n", path)
+	}
+
+	return err
 }
 
-func drawHexes(path string, hv []hexes.Hex, l hexes.Layout) error {
-	// create a new gg image
-	dc := gg.NewContext(1024, 1024)
+// biomeColors are the fill colors for the default biomes.
+var biomeColors = map[string]color.Color{
+	"ocean":       color.RGBA{R: 0x44, G: 0x66, B: 0xaa, A: 0xff},
+	"lake":        color.RGBA{R: 0x55, G: 0x88, B: 0xcc, A: 0xff},
+	"snow":        color.RGBA{R: 0xf8, G: 0xf8, B: 0xf8, A: 0xff},
+	"tundra":      color.RGBA{R: 0xbb, G: 0xbb, B: 0xaa, A: 0xff},
+	"taiga":       color.RGBA{R: 0x99, G: 0xaa, B: 0x77, A: 0xff},
+	"shrubland":   color.RGBA{R: 0x88, G: 0x99, B: 0x77, A: 0xff},
+	"rain forest": color.RGBA{R: 0x33, G: 0x77, B: 0x55, A: 0xff},
+	"forest":      color.RGBA{R: 0x66, G: 0x99, B: 0x55, A: 0xff},
+	"grassland":   color.RGBA{R: 0x88, G: 0xaa, B: 0x55, A: 0xff},
+	"desert":      color.RGBA{R: 0xd2, G: 0xb9, B: 0x8b, A: 0xff},
+}
 
-	// draw them
+// drawTerrain generates a world from the seed and draws it with its rivers.
+func drawTerrain(path string, seed int64) error {
+	const radius, margin = 20, 10
+
+	// move the origin so that the map starts at the margin
+	l := hexes.NewFlatOddLayout(hexes.NewPoint(12, 12), hexes.NewPoint(0, 0))
+	region := hexes.NewRangeRegion(hexes.NewHex(0, 0, 0), radius)
+	topLeft, bottomRight := raster.Bounds(l, region.Hexes())
+	l = hexes.NewFlatOddLayout(l.Size(), hexes.NewPoint(margin-topLeft.X, margin-topLeft.Y))
+
+	world := terrain.Generate(l, region, terrain.Options{
+		Seed:        seed,
+		EdgeFalloff: 4,
+	})
+	rivers := terrain.TraceRivers(world, terrain.RiverOptions{})
+
+	img := raster.Draw(l, world.Hexes(), raster.Options{
+		Width:     int(math.Ceil(bottomRight.X - topLeft.X + 2*margin)),
+		Height:    int(math.Ceil(bottomRight.Y - topLeft.Y + 2*margin)),
+		LineWidth: 1,
+		LineColor: color.RGBA{R: 0x44, G: 0x44, B: 0x44, A: 0xff},
+		Fill: func(h hexes.Hex) color.Color {
+			t, _ := world.Get(h)
+			return biomeColors[t.Biome]
+		},
+	})
+
+	dc := gg.NewContextForImage(img)
+	dc.SetColor(biomeColors["lake"])
+	dc.SetLineCapRound()
+	for _, segment := range rivers.Segments(l) {
+		dc.SetLineWidth(min(1+float64(segment.Flow)/16, 5))
+		dc.DrawLine(segment.From.X, segment.From.Y, segment.To.X, segment.To.Y)
+		dc.Stroke()
+	}
+
+	err := gg.SavePNG(path, dc.Image())
+	if err == nil {
+		log.Printf("created %s\n", path)
+	}
+
+	return err
+}
+
+// drawFog draws what a scout has seen of a generated world after walking across it.
+func drawFog(path string, seed int64) error {
+	const radius, turns = 12, 8
+
+	l := hexes.NewFlatOddLayout(hexes.NewPoint(16, 16), hexes.NewPoint(0, 0))
+	region := hexes.NewRangeRegion(hexes.NewHex(0, 0, 0), radius)
+	world := terrain.Generate(l, region, terrain.Options{Seed: seed, EdgeFalloff: 3})
+
+	// the scout walks one hex per turn and sees everything within two hexes.
+	scout := fog.NewKnowledge[terrain.Hex](0)
+	route := hexes.NewHex(-turns, turns/2, turns/2).LineTo(hexes.NewHex(0, 0, 0))
+	for turn, at := range route {
+		scout.ObserveAll(turn+1, hexes.NewRangeRegion(at, 2).Hexes(), func(h hexes.Hex) terrain.Hex {
+			t, _ := world.Get(h)
+			return t
+		})
+	}
+
+	img := scout.Draw(l, world.Hexes(), len(route), fog.DrawOptions[terrain.Hex]{
+		Options: raster.Options{
+			Margin:    10,
+			LineWidth: 1,
+			LineColor: color.RGBA{R: 0x44, G: 0x44, B: 0x44, A: 0xff},
+		},
+		Color: func(h hexes.Hex, t terrain.Hex) color.Color {
+			return biomeColors[t.Biome]
+		},
+	})
+
+	err := gg.SavePNG(path, img)
+	if err == nil {
+		log.Printf("created %s\n", path)
+	}
+
+	return err
+}
+
+// drawDiff compares a map saved with an odd layout to the next turn's map saved
+// with an even layout and highlights the changes.
+func drawDiff(path string) error {
+	const columns, rows, margin = 8, 6, 10
+
+	odd := hexes.NewFlatOddLayout(hexes.NewPoint(24, 24), hexes.NewPoint(margin+24, margin+24))
+	even := hexes.NewFlatEvenLayout(odd.Size(), odd.Origin())
+	hv := hexes.NewRectangleGrid[bool](odd, columns, rows).Hexes()
+
+	// the maps as they were saved, keyed by offset coordinates
+	before := make(map[diff.Offset]map[string]string)
 	for _, h := range hv {
-		cp, corners := l.Points(h)
-		dc.DrawCircle(cp.X, cp.Y, 3)
-		dc.SetRGB(0, 0, 0)
-		dc.Fill()
-
-		column, row := l.HexToOffset(h)
-		dc.DrawStringAnchored(fmt.Sprintf("%d, %d", column, row), cp.X, cp.Y, 0.5, 0.5)
-
-		pt := corners[5]
-		x1, y1 := pt.X, pt.Y
-		for i := 0; i < 6; i++ {
-			pt = corners[i]
-			x2, y2 := pt.X, pt.Y
-			dc.SetRGB(25, 25, 25)
-			dc.SetLineWidth(2)
-			dc.DrawLine(x1, y1, x2, y2)
-			dc.Stroke()
-			x1, y1 = x2, y2
-		}
+		column, row := odd.HexToOffset(h)
+		before[diff.Offset{Column: column, Row: row}] = map[string]string{"terrain": "grassland"}
+	}
+	after := make(map[diff.Offset]map[string]string)
+	for _, h := range hv[:len(hv)-3] {
+		column, row := even.HexToOffset(h)
+		after[diff.Offset{Column: column, Row: row}] = map[string]string{"terrain": "grassland"}
+	}
+	column, row := even.HexToOffset(hv[10])
+	after[diff.Offset{Column: column, Row: row}] = map[string]string{"terrain": "forest"}
+	column, row = even.HexToOffset(hv[20])
+	after[diff.Offset{Column: column, Row: row}] = map[string]string{"terrain": "grassland", "road": "yes"}
+	added := odd.OffsetToHex(columns, 0)
+	column, row = even.HexToOffset(added)
+	after[diff.Offset{Column: column, Row: row}] = map[string]string{"terrain": "desert"}
+
+	d := diff.Compare(diff.FromOffsets(odd, before), diff.FromOffsets(even, after))
+	for _, c := range d.Changes {
+		column, row := odd.HexToOffset(c.Hex)
+		log.Printf("%d, %d %s %v\n", column, row, c.Kind, c.Attributes)
 	}
 
-	err := dc.SavePNG(path)
+	all := append(hv, added)
+	_, bottomRight := raster.Bounds(odd, all)
+	img := raster.Draw(odd, all, raster.Options{
+		Width:  int(bottomRight.X + margin),
+		Height: int(bottomRight.Y + margin),
+		Fill: func(h hexes.Hex) color.Color {
+			return biomeColors["grassland"]
+		},
+	})
+
+	err := gg.SavePNG(path, d.Overlay(img, odd, diff.OverlayOptions{}))
 	if err == nil {
 		log.Printf("created %s\n", path)
 	}
@@ -109,118 +300,180 @@ func drawHexes(path string, hv []hexes.Hex, l hexes.Layout) error {
 	return err
 }
 
-func drawHexesOnImage(input, output string) error {
-	// Load the PNG image from the file
-	img, err := gg.LoadImage(input)
+// drawCamera draws a hexagon of hexes through a camera that zooms in and
+// rotates the map around the center of the canvas.
+func drawCamera(path string) error {
+	const width, height = 512, 512
+	center := hexes.NewPoint(width/2, height/2)
+
+	l, err := hexes.NewCameraLayout(hexes.NewPointyOddLayout(hexes.NewPoint(20, 20), center), hexes.Identity().
+		Translate(-center.X, -center.Y).
+		Scale(1.25, 1.25).
+		Rotate(math.Pi/12).
+		Translate(center.X, center.Y))
 	if err != nil {
-		return errors.Join(fmt.Errorf("failed to load image", err))
+		return err
 	}
 
-	// Create a new drawing context with the size of the image
-	dc := gg.NewContextForImage(img)
+	img := raster.Draw(l, hexes.NewHexagonGrid[bool](5).Hexes(), raster.Options{
+		Width:     width,
+		Height:    height,
+		LineWidth: 2,
+		Label:     raster.OffsetLabel(l),
+	})
 
-	// Get the width and height of the image
-	mapWidth, mapHeight := float64(dc.Width()), float64(dc.Height())
-	log.Printf("hexes: map width  %8.2f x %8.2f\n", mapWidth, mapHeight)
-	cx, cy := mapWidth/2, mapHeight/2
-	log.Printf("hexes: map center %8.2f x %8.2f\n", cx, cy)
+	err = gg.SavePNG(path, img)
+	if err == nil {
+		log.Printf("created %s\n", path)
+	}
 
-	// we want to get about 100 hexes onto the map
-	hexWidth, hexHeight := 12.5, 12.5 //(mapWidth - 10) / 100
-	hexesWide := int(math.Ceil(mapWidth / hexWidth))
-	log.Printf("hexes: hex width  %8.2f x %8.2f\n", hexWidth, hexHeight)
-	log.Printf("hexes: hexes wide %8d\n", hexesWide)
+	return err
+}
 
-	layout := hexes.NewFlatOddLayout(hexes.NewPoint(hexWidth, hexHeight), hexes.NewPoint(float64(hexWidth)/2, float64(hexHeight)/2))
+// drawLasso fills the hexes whose centers are inside a lasso drawn over the map
+// and outlines the lasso.
+func drawLasso(path string) error {
+	const width, height = 512, 384
+
+	l := hexes.NewFlatEvenLayout(hexes.NewPoint(20, 16), hexes.NewPoint(0, 0))
+	lasso := []hexes.Point{{X: 60, Y: 40}, {X: 300, Y: 70}, {X: 460, Y: 40}, {X: 420, Y: 330}, {X: 250, Y: 180}, {X: 80, Y: 300}}
+	selected := hexes.NewRegion(hexes.HexesInPolygon(l, lasso)...)
+
+	img := raster.Draw(l, hexes.HexesInRect(l, hexes.NewPoint(0, 0), hexes.NewPoint(width, height)), raster.Options{
+		Width:  width,
+		Height: height,
+		Fill: func(h hexes.Hex) color.Color {
+			if selected.Contains(h) {
+				return color.RGBA{R: 0xee, G: 0xcc, B: 0x66, A: 0xff}
+			}
+			return nil
+		},
+	})
 
-	hv := newSquareMap(layout, int(math.Ceil(mapWidth/hexWidth)))
+	dc := gg.NewContextForImage(img)
+	for _, pt := range lasso {
+		dc.LineTo(pt.X, pt.Y)
+	}
+	dc.ClosePath()
+	dc.SetColor(color.RGBA{R: 0xcc, G: 0x22, B: 0x22, A: 0xff})
+	dc.SetLineWidth(2)
+	dc.Stroke()
 
-	// draw them
-	for _, h := range hv {
-		_, corners := layout.Points(h)
-		pt := corners[5]
-		x1, y1 := pt.X, pt.Y
-		for i := 0; i < 6; i++ {
-			pt = corners[i]
-			x2, y2 := pt.X, pt.Y
-			dc.SetRGB(25, 25, 25)
-			dc.SetLineWidth(1)
-			dc.DrawLine(x1, y1, x2, y2)
-			dc.Stroke()
-			x1, y1 = x2, y2
-		}
+	err := gg.SavePNG(path, dc.Image())
+	if err == nil {
+		log.Printf("created %s\n", path)
 	}
 
-	// Save the modified image as a new PNG file
-	if err := dc.SavePNG(output); err != nil {
-		return errors.Join(fmt.Errorf("failed to save image", err))
+	return err
+}
+
+func drawSVG(path string, hv []hexes.Hex, l hexes.Layout) error {
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	err = svg.Render(fp, l, hv, svg.Options{
+		Labels: true,
+		Margin: 10,
+		Style: func(h hexes.Hex) svg.Style {
+			if h.Length()%2 == 0 {
+				return svg.Style{Fill: "lightgreen", Layer: "even"}
+			}
+			return svg.Style{Fill: "khaki", Layer: "odd"}
+		},
+	})
+	if err == nil {
+		log.Printf("created %s\n", path)
 	}
 
-	return nil
+	return err
 }
 
-func drawHexesOnScaledImage(input, output string) error {
-	// Load the PNG image from the file
-	img, err := gg.LoadImage(input)
+// saveMap writes the shape as a text map file and reads it back.
+func saveMap(path string, shape mapfile.Shape, l hexes.Layout) error {
+	hv, err := shape.Hexes(l)
 	if err != nil {
-		return errors.Join(fmt.Errorf("failed to load image", err))
+		return err
 	}
 
-	// Get the original width and height of the image
-	origWidth, origHeight := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
-	log.Printf("hexes: map width  %8.2f x %8.2f\n", origWidth, origHeight)
+	fp, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
 
-	// Calculate new dimensions (25% wider and taller)
-	newWidth, newHeight := origWidth*1.25, origHeight*1.25
-	log.Printf("hexes: map width  %8.2f x %8.2f\n", newWidth, newHeight)
+	w, err := mapfile.NewTextWriter(fp, mapfile.HeaderFor(l, shape))
+	if err != nil {
+		return err
+	}
+	for _, h := range hv {
+		record := mapfile.Hex{Hex: h, Terrain: "plains"}
+		if h.Length()%2 == 0 {
+			record.Terrain = "forest"
+			record.Attributes = map[string]string{"distance": fmt.Sprintf("%d", h.Length())}
+		}
+		if err = w.WriteHex(record); err != nil {
+			return err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
 
-	// Create a new drawing context with the new size
-	dc := gg.NewContext(int(newWidth), int(newHeight))
+	if _, err = fp.Seek(0, 0); err != nil {
+		return err
+	}
+	r, err := mapfile.NewTextReader(fp)
+	if err != nil {
+		return err
+	}
+	m, err := mapfile.ReadAll(r)
+	if err != nil {
+		return err
+	} else if len(m.Hexes) != len(hv) {
+		return fmt.Errorf("%s: read %d hexes, wrote %d", path, len(m.Hexes), len(hv))
+	}
+	log.Printf("created %s\n", path)
 
-	// Scale the drawing context
-	dc.Scale(1.25, 1.25)
+	return nil
+}
 
-	// Draw the original image on the scaled context
-	dc.DrawImage(img, 0, 0)
+func drawHexesOnImage(input, output string) error {
+	return drawHexesOnScaledImage(input, output, 1)
+}
 
-	// Reset the scaling for further drawing operations
-	dc.Identity()
+func drawHexesOnScaledImage(input, output string, scale float64) error {
+	// Load the PNG image from the file
+	img, err := gg.LoadImage(input)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to load image: %w", err))
+	}
 
-	// Get the width and height of the image
-	mapWidth, mapHeight := float64(dc.Width()), float64(dc.Height())
+	// Get the width and height of the image after scaling
+	mapWidth, mapHeight := float64(img.Bounds().Dx())*scale, float64(img.Bounds().Dy())*scale
 	log.Printf("hexes: map width  %8.2f x %8.2f\n", mapWidth, mapHeight)
-	cx, cy := mapWidth/2, mapHeight/2
-	log.Printf("hexes: map center %8.2f x %8.2f\n", cx, cy)
 
-	// we want to get about 100 hexes onto the map
-	hexWidth, hexHeight := 12.5, 12.5 //(mapWidth - 10) / 100
-	hexesWide := int(math.Ceil(mapWidth / hexWidth))
+	hexWidth, hexHeight := 12.5, 12.5
 	log.Printf("hexes: hex width  %8.2f x %8.2f\n", hexWidth, hexHeight)
-	log.Printf("hexes: hexes wide %8d\n", hexesWide)
 
 	layout := hexes.NewFlatOddLayout(hexes.NewPoint(hexWidth, hexHeight), hexes.NewPoint(float64(hexWidth)/2, float64(hexHeight)/2))
 
-	hv := newSquareMap(layout, int(math.Ceil(mapWidth/hexWidth)))
+	// draw only the hexes that cover the image
+	hv := hexes.HexesInRect(layout, hexes.NewPoint(0, 0), hexes.NewPoint(mapWidth, mapHeight))
+	log.Printf("hexes: hexes      %8d\n", len(hv))
 
-	// draw them
-	for _, h := range hv {
-		_, corners := layout.Points(h)
-		pt := corners[5]
-		x1, y1 := pt.X, pt.Y
-		for i := 0; i < 6; i++ {
-			pt = corners[i]
-			x2, y2 := pt.X, pt.Y
-			dc.SetRGB(25, 25, 25)
-			dc.SetLineWidth(1)
-			dc.DrawLine(x1, y1, x2, y2)
-			dc.Stroke()
-			x1, y1 = x2, y2
-		}
-	}
+	// draw them over the scaled image
+	dst := raster.Draw(layout, hv, raster.Options{
+		Background:      img,
+		BackgroundScale: scale,
+		LineColor:       color.RGBA{R: 25, G: 25, B: 25, A: 255},
+	})
 
 	// Save the modified image as a new PNG file
-	if err := dc.SavePNG(output); err != nil {
-		return errors.Join(fmt.Errorf("failed to save image", err))
+	if err := gg.SavePNG(output, dst); err != nil {
+		return errors.Join(fmt.Errorf("failed to save image: %w", err))
 	}
 
 	return nil
@@ -0,0 +1,148 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package diff compares two hex maps, such as the map before and after a turn.
+//
+// A map is a set of attributes, like "terrain" or "owner", for each hex.
+// Maps are keyed by cube coordinates, so maps that were stored with offset
+// coordinates are converted through their own layout first. That lets a map
+// saved with an even layout be compared to one saved with an odd layout.
+package diff
+
+import (
+	"github.com/playbymail/hexes"
+	"sort"
+)
+
+// Map is the attributes of each hex.
+type Map map[hexes.Hex]map[string]string
+
+// Offset is the column and row of a hex in a layout.
+type Offset struct {
+	Column, Row int
+}
+
+// FromOffsets returns the map with its offset coordinates converted through the layout.
+func FromOffsets(layout hexes.Layout, m map[Offset]map[string]string) Map {
+	results := make(Map, len(m))
+	for o, attributes := range m {
+		results[layout.OffsetToHex(o.Column, o.Row)] = attributes
+	}
+	return results
+}
+
+// Kind is the kind of change.
+type Kind int
+
+const (
+	Added Kind = iota
+	Removed
+	Modified
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "Kind(?)"
+}
+
+// Attribute is a change to one attribute of a hex.
+// Before is empty for added attributes and After is empty for removed ones.
+type Attribute struct {
+	Name          string
+	Kind          Kind
+	Before, After string
+}
+
+// Change is a hex that was added, removed or modified.
+type Change struct {
+	Hex        hexes.Hex
+	Kind       Kind
+	Attributes []Attribute // ordered by name
+}
+
+// Diff is the changes between two maps.
+type Diff struct {
+	Changes []Change // ordered by r, then q
+}
+
+// Compare returns the changes that turn the before map into the after map.
+// Hexes with the same attributes in both maps are not included.
+func Compare(before, after Map) *Diff {
+	d := &Diff{}
+	for h, b := range before {
+		a, ok := after[h]
+		if !ok {
+			d.Changes = append(d.Changes, Change{Hex: h, Kind: Removed, Attributes: compare_attributes(b, nil)})
+		} else if attributes := compare_attributes(b, a); len(attributes) != 0 {
+			d.Changes = append(d.Changes, Change{Hex: h, Kind: Modified, Attributes: attributes})
+		}
+	}
+	for h, a := range after {
+		if _, ok := before[h]; !ok {
+			d.Changes = append(d.Changes, Change{Hex: h, Kind: Added, Attributes: compare_attributes(nil, a)})
+		}
+	}
+	sort.Slice(d.Changes, func(i, j int) bool {
+		a, b := d.Changes[i].Hex, d.Changes[j].Hex
+		if a.R() != b.R() {
+			return a.R() < b.R()
+		}
+		return a.Q() < b.Q()
+	})
+	return d
+}
+
+// compare_attributes returns the attributes that differ, ordered by name.
+func compare_attributes(before, after map[string]string) (results []Attribute) {
+	for name, b := range before {
+		if a, ok := after[name]; !ok {
+			results = append(results, Attribute{Name: name, Kind: Removed, Before: b})
+		} else if a != b {
+			results = append(results, Attribute{Name: name, Kind: Modified, Before: b, After: a})
+		}
+	}
+	for name, a := range after {
+		if _, ok := before[name]; !ok {
+			results = append(results, Attribute{Name: name, Kind: Added, After: a})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// Added returns the hexes that are only in the after map.
+func (d *Diff) Added() []Change {
+	return d.filter(Added)
+}
+
+// Removed returns the hexes that are only in the before map.
+func (d *Diff) Removed() []Change {
+	return d.filter(Removed)
+}
+
+// Modified returns the hexes that are in both maps with different attributes.
+func (d *Diff) Modified() []Change {
+	return d.filter(Modified)
+}
+
+func (d *Diff) filter(kind Kind) (results []Change) {
+	for _, c := range d.Changes {
+		if c.Kind == kind {
+			results = append(results, c)
+		}
+	}
+	return results
+}
+
+// Empty returns true if the maps are the same.
+func (d *Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package diff
+
+import (
+	"github.com/playbymail/hexes"
+	"slices"
+	"testing"
+)
+
+// test_world returns a map of the hexes within radius 3 of the origin, each with a different terrain.
+func test_world() Map {
+	m := make(Map)
+	for i, h := range hexes.NewHexagonGrid[bool](3).Hexes() {
+		m[h] = map[string]string{"terrain": string(rune('a' + i))}
+	}
+	return m
+}
+
+// to_offsets returns the map keyed by the offset coordinates of the layout.
+func to_offsets(layout hexes.Layout, m Map) map[Offset]map[string]string {
+	results := make(map[Offset]map[string]string, len(m))
+	for h, attributes := range m {
+		column, row := layout.HexToOffset(h)
+		copied := make(map[string]string, len(attributes))
+		for name, value := range attributes {
+			copied[name] = value
+		}
+		results[Offset{Column: column, Row: row}] = copied
+	}
+	return results
+}
+
+func TestCompareOffsets(t *testing.T) {
+	size, origin := hexes.NewPoint(10, 10), hexes.NewPoint(0, 0)
+	for _, tc := range []struct {
+		name      string
+		even, odd hexes.Layout
+	}{
+		{"flat", hexes.NewFlatEvenLayout(size, origin), hexes.NewFlatOddLayout(size, origin)},
+		{"pointy", hexes.NewPointyEvenLayout(size, origin), hexes.NewPointyOddLayout(size, origin)},
+	} {
+		world := test_world()
+		even, odd := to_offsets(tc.even, world), to_offsets(tc.odd, world)
+
+		// the same map saved with either layout compares equal once it is read back through its own layout.
+		if d := Compare(FromOffsets(tc.even, even), FromOffsets(tc.odd, odd)); !d.Empty() {
+			t.Errorf("%s: even to odd: want no changes: got %v", tc.name, d.Changes)
+		}
+
+		// reading the odd map as if it were even moves every hex in an odd row or column.
+		if d := Compare(FromOffsets(tc.even, even), FromOffsets(tc.even, odd)); d.Empty() {
+			t.Errorf("%s: odd read as even: want changes: got none", tc.name)
+		}
+
+		// a change at an offset in an odd row or column is reported at the hex the odd layout puts there.
+		h := hexes.NewHex(1, -1, 0)
+		column, row := tc.odd.HexToOffset(h)
+		if ec, er := tc.even.HexToOffset(h); ec == column && er == row {
+			t.Fatalf("%s: %v: want different offsets in the two layouts", tc.name, h)
+		}
+		odd[Offset{Column: column, Row: row}]["terrain"] = "burnt"
+		d := Compare(FromOffsets(tc.even, even), FromOffsets(tc.odd, odd))
+		want := []Change{{Hex: h, Kind: Modified, Attributes: []Attribute{{Name: "terrain", Kind: Modified, Before: world[h]["terrain"], After: "burnt"}}}}
+		if len(d.Changes) != 1 || d.Changes[0].Hex != h || !slices.Equal(d.Changes[0].Attributes, want[0].Attributes) {
+			t.Errorf("%s: want %v: got %v", tc.name, want, d.Changes)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a, b, c, e := hexes.NewHex(0, 0, 0), hexes.NewHex(1, -1, 0), hexes.NewHex(0, 1, -1), hexes.NewHex(-1, 1, 0)
+	before := Map{
+		a: {"terrain": "forest", "owner": "red"},
+		b: {"terrain": "swamp"},
+		c: {"terrain": "hills", "road": "yes"},
+	}
+	after := Map{
+		a: {"terrain": "forest", "owner": "red"},
+		c: {"terrain": "mountains", "owner": "blue"},
+		e: {"terrain": "plains"},
+	}
+	d := Compare(before, after)
+
+	// the changes are ordered by r, then q, and the attributes by name.
+	want := []Change{
+		{Hex: b, Kind: Removed, Attributes: []Attribute{{Name: "terrain", Kind: Removed, Before: "swamp"}}},
+		{Hex: e, Kind: Added, Attributes: []Attribute{{Name: "terrain", Kind: Added, After: "plains"}}},
+		{Hex: c, Kind: Modified, Attributes: []Attribute{
+			{Name: "owner", Kind: Added, After: "blue"},
+			{Name: "road", Kind: Removed, Before: "yes"},
+			{Name: "terrain", Kind: Modified, Before: "hills", After: "mountains"},
+		}},
+	}
+	if len(d.Changes) != len(want) {
+		t.Fatalf("Compare: want %v: got %v", want, d.Changes)
+	}
+	for i := range want {
+		if got := d.Changes[i]; got.Hex != want[i].Hex || got.Kind != want[i].Kind || !slices.Equal(got.Attributes, want[i].Attributes) {
+			t.Errorf("Changes[%d]: want %v: got %v", i, want[i], got)
+		}
+	}
+
+	for _, tc := range []struct {
+		kind Kind
+		got  []Change
+		want hexes.Hex
+	}{
+		{Added, d.Added(), e},
+		{Removed, d.Removed(), b},
+		{Modified, d.Modified(), c},
+	} {
+		if len(tc.got) != 1 || tc.got[0].Hex != tc.want {
+			t.Errorf("%v: want %v: got %v", tc.kind, tc.want, tc.got)
+		}
+	}
+	if !Compare(before, before).Empty() {
+		t.Errorf("Compare with itself: want no changes")
+	}
+}
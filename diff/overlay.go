@@ -0,0 +1,69 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package diff
+
+import (
+	"github.com/fogleman/gg"
+	"github.com/playbymail/hexes"
+	"image"
+	"image/color"
+)
+
+// OverlayOptions controls how the changes are highlighted.
+type OverlayOptions struct {
+	// Added, Removed and Modified are the outline colors for each kind of change.
+	// Nil means green, red and orange. The hexes are filled with the same color
+	// at a quarter of its opacity.
+	Added, Removed, Modified color.Color
+
+	LineWidth float64 // width of the outlines, zero means 3
+}
+
+func (o OverlayOptions) color(kind Kind) color.Color {
+	switch kind {
+	case Added:
+		if o.Added != nil {
+			return o.Added
+		}
+		return color.RGBA{R: 0x22, G: 0xaa, B: 0x22, A: 0xff}
+	case Removed:
+		if o.Removed != nil {
+			return o.Removed
+		}
+		return color.RGBA{R: 0xcc, G: 0x22, B: 0x22, A: 0xff}
+	}
+	if o.Modified != nil {
+		return o.Modified
+	}
+	return color.RGBA{R: 0xee, G: 0x88, B: 0x00, A: 0xff}
+}
+
+// Overlay returns a copy of the image with the changed hexes highlighted.
+// The image must be drawn in the coordinates of the layout, for example
+// by raster.Draw with an explicit Width and Height.
+func (d *Diff) Overlay(img image.Image, layout hexes.Layout, opts OverlayOptions) image.Image {
+	if opts.LineWidth == 0 {
+		opts.LineWidth = 3
+	}
+
+	dc := gg.NewContextForImage(img)
+	dc.SetLineWidth(opts.LineWidth)
+	for _, c := range d.Changes {
+		_, corners := layout.Points(c.Hex)
+		for i, pt := range corners {
+			if i == 0 {
+				dc.MoveTo(pt.X, pt.Y)
+			} else {
+				dc.LineTo(pt.X, pt.Y)
+			}
+		}
+		dc.ClosePath()
+		outline := opts.color(c.Kind)
+		r, g, b, a := outline.RGBA()
+		dc.SetColor(color.RGBA64{R: uint16(r / 4), G: uint16(g / 4), B: uint16(b / 4), A: uint16(a / 4)})
+		dc.FillPreserve()
+		dc.SetColor(outline)
+		dc.Stroke()
+	}
+	return dc.Image()
+}
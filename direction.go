@@ -0,0 +1,122 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"strings"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// directions
+//
+// Directions follow hex_directions, counter-clockwise from 0 to 5.
+// The same direction has a different compass name for flat and pointy hexes:
+//   direction   flat               pointy
+//       0       southeast  (SE)    east      (E)
+//       1       northeast  (NE)    northeast (NE)
+//       2       north      (N)     northwest (NW)
+//       3       northwest  (NW)    west      (W)
+//       4       southwest  (SW)    southwest (SW)
+//       5       south      (S)     southeast (SE)
+
+var (
+	// ErrInvalidDirection is returned when the text is not a direction for the orientation.
+	ErrInvalidDirection = errors.New("invalid direction")
+)
+
+// Direction is one of the six directions from a hex to its neighbors.
+type Direction int
+
+var (
+	flat_direction_names     = [6]string{"southeast", "northeast", "north", "northwest", "southwest", "south"}
+	flat_direction_abbrevs   = [6]string{"SE", "NE", "N", "NW", "SW", "S"}
+	pointy_direction_names   = [6]string{"east", "northeast", "northwest", "west", "southwest", "southeast"}
+	pointy_direction_abbrevs = [6]string{"E", "NE", "NW", "W", "SW", "SE"}
+)
+
+// normalize returns the direction as an index from 0 to 5.
+func (d Direction) normalize() int {
+	return (6 + (int(d) % 6)) % 6
+}
+
+// Opposite returns the direction pointing the other way.
+func (d Direction) Opposite() Direction {
+	return d.Rotate(3)
+}
+
+// Rotate returns the direction turned n steps of 60 degrees counter-clockwise.
+// Negative values of n turn clockwise.
+func (d Direction) Rotate(n int) Direction {
+	return Direction((6 + ((d.normalize() + n) % 6)) % 6)
+}
+
+// Between returns the number of steps that a must be rotated to point in direction b.
+// The result is from -2 to 3, with positive values being counter-clockwise.
+func Between(a, b Direction) int {
+	steps := (6 + b.normalize() - a.normalize()) % 6
+	if steps > 3 {
+		steps -= 6
+	}
+	return steps
+}
+
+// Vector returns the offset to the neighbor in this direction.
+func (d Direction) Vector() Hex {
+	return hex_direction(int(d))
+}
+
+// Angle returns the screen angle of the direction in degrees.
+// Screen angles start at the positive x-axis and increase clockwise because the y-axis points down.
+func (d Direction) Angle(orientation Orientation) float64 {
+	if orientation.IsFlat() {
+		return float64((390 - 60*d.normalize()) % 360)
+	}
+	return float64((360 - 60*d.normalize()) % 360)
+}
+
+// Name returns the compass name of the direction for the orientation.
+func (d Direction) Name(orientation Orientation) string {
+	if orientation.IsFlat() {
+		return flat_direction_names[d.normalize()]
+	}
+	return pointy_direction_names[d.normalize()]
+}
+
+// Abbrev returns the compass abbreviation of the direction for the orientation.
+func (d Direction) Abbrev(orientation Orientation) string {
+	if orientation.IsFlat() {
+		return flat_direction_abbrevs[d.normalize()]
+	}
+	return pointy_direction_abbrevs[d.normalize()]
+}
+
+// ParseDirection returns the direction for a compass name or abbreviation.
+// The names and abbreviations depend on the orientation, so "E" is not a direction
+// for flat hexes and "N" is not a direction for pointy hexes.
+// Case is ignored, and "north-east" and "north east" are accepted for "northeast."
+func ParseDirection(orientation Orientation, s string) (Direction, error) {
+	names, abbrevs := flat_direction_names, flat_direction_abbrevs
+	if orientation.IsPointy() {
+		names, abbrevs = pointy_direction_names, pointy_direction_abbrevs
+	}
+	text := strings.NewReplacer("-", "", " ", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(s)))
+	for i := 0; i < 6; i++ {
+		if text == names[i] || text == strings.ToLower(abbrevs[i]) {
+			return Direction(i), nil
+		}
+	}
+	return 0, &ParseError{Func: "ParseDirection", Input: s, Err: ErrInvalidDirection}
+}
+
+// DirectionTo returns the direction from the hex to an adjacent hex.
+// It returns false if the hexes are not neighbors.
+func (h Hex) DirectionTo(b Hex) (Direction, bool) {
+	delta := hex_subtract(b, h)
+	for i := 0; i < 6; i++ {
+		if delta == hex_directions[i] {
+			return Direction(i), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import "fmt"
+
+// --------------------------------------------------------------------------------------------------------------------
+// edges and vertices are from https://www.redblobgames.com/grids/parts/
+//
+// Every edge is shared by two hexes and every vertex is shared by three.
+// To give each one a single identity, we store them in a canonical form:
+//   an edge is a hex plus a direction from 0 to 2
+//   a vertex is a hex plus a direction of 0 or 1
+// The vertex in direction d is the corner shared by the hex and its
+// neighbors in directions d and d+1.
+
+// Edge is the boundary between two adjacent hexes.
+type Edge struct {
+	hex       Hex
+	direction int
+}
+
+// NewEdge returns the edge between the hex and its neighbor in the given direction.
+func NewEdge(h Hex, direction Direction) Edge {
+	d := direction.normalize()
+	if d > 2 {
+		return Edge{hex: hex_neighbor(h, d), direction: d - 3}
+	}
+	return Edge{hex: h, direction: d}
+}
+
+// Hex returns the hex that owns the edge in canonical form.
+func (e Edge) Hex() Hex {
+	return e.hex
+}
+
+// Direction returns the direction of the edge in canonical form, from 0 to 2.
+func (e Edge) Direction() Direction {
+	return Direction(e.direction)
+}
+
+func (e Edge) String() string {
+	return fmt.Sprintf("edge(%d, %d, %d; %d)", e.hex.q, e.hex.r, e.hex.s, e.direction)
+}
+
+// Hexes returns the two hexes on either side of the edge.
+func (e Edge) Hexes() (Hex, Hex) {
+	return e.hex, hex_neighbor(e.hex, e.direction)
+}
+
+// Vertices returns the two vertices at the ends of the edge.
+func (e Edge) Vertices() (Vertex, Vertex) {
+	return NewVertex(e.hex, Direction(e.direction-1)), NewVertex(e.hex, Direction(e.direction))
+}
+
+// Neighbors returns the four edges that share a vertex with this edge.
+func (e Edge) Neighbors() (results [4]Edge) {
+	a, b := e.Vertices()
+	i := 0
+	for _, v := range [2]Vertex{a, b} {
+		for _, edge := range v.Edges() {
+			if edge != e {
+				results[i] = edge
+				i++
+			}
+		}
+	}
+	return results
+}
+
+// Points returns the screen coordinates of the ends of the edge.
+func (e Edge) Points(layout Layout) (Point, Point) {
+	_, corners := layout.Points(e.hex)
+	a, b := hex_edge_corners(layout.Orientation(), e.direction)
+	return corners[a], corners[b]
+}
+
+// Edge returns the edge of the hex in the given direction.
+func (h Hex) Edge(direction Direction) Edge {
+	return NewEdge(h, direction)
+}
+
+// Edges returns the six edges of the hex in direction order.
+func (h Hex) Edges() (results [6]Edge) {
+	for direction := 0; direction < 6; direction++ {
+		results[direction] = NewEdge(h, Direction(direction))
+	}
+	return results
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// vertices
+
+// Vertex is a corner shared by three hexes.
+type Vertex struct {
+	hex       Hex
+	direction int
+}
+
+// NewVertex returns the corner shared by the hex and its neighbors
+// in the given direction and the next one counter-clockwise.
+func NewVertex(h Hex, direction Direction) Vertex {
+	switch direction.normalize() {
+	case 0:
+		return Vertex{hex: h, direction: 0}
+	case 1:
+		return Vertex{hex: h, direction: 1}
+	case 2:
+		return Vertex{hex: hex_neighbor(h, 3), direction: 0}
+	case 3:
+		return Vertex{hex: hex_neighbor(h, 4), direction: 1}
+	case 4:
+		return Vertex{hex: hex_neighbor(h, 4), direction: 0}
+	}
+	return Vertex{hex: hex_neighbor(h, 5), direction: 1}
+}
+
+// Hex returns the hex that owns the vertex in canonical form.
+func (v Vertex) Hex() Hex {
+	return v.hex
+}
+
+// Direction returns the direction of the vertex in canonical form, either 0 or 1.
+func (v Vertex) Direction() Direction {
+	return Direction(v.direction)
+}
+
+func (v Vertex) String() string {
+	return fmt.Sprintf("vertex(%d, %d, %d; %d)", v.hex.q, v.hex.r, v.hex.s, v.direction)
+}
+
+// Hexes returns the three hexes that share the vertex.
+func (v Vertex) Hexes() [3]Hex {
+	return [3]Hex{v.hex, hex_neighbor(v.hex, v.direction), hex_neighbor(v.hex, v.direction+1)}
+}
+
+// Edges returns the three edges that meet at the vertex.
+func (v Vertex) Edges() [3]Edge {
+	return [3]Edge{
+		NewEdge(v.hex, Direction(v.direction)),
+		NewEdge(v.hex, Direction(v.direction+1)),
+		NewEdge(hex_neighbor(v.hex, v.direction), Direction(v.direction+2)),
+	}
+}
+
+// Neighbors returns the three vertices at the other ends of the edges that meet at the vertex.
+// The neighbor at index i is at the other end of Edges()[i].
+func (v Vertex) Neighbors() [3]Vertex {
+	return [3]Vertex{
+		NewVertex(v.hex, Direction(v.direction-1)),
+		NewVertex(v.hex, Direction(v.direction+1)),
+		NewVertex(hex_neighbor(v.hex, v.direction), Direction(v.direction+1)),
+	}
+}
+
+// Point returns the screen coordinates of the vertex.
+func (v Vertex) Point(layout Layout) Point {
+	_, corners := layout.Points(v.hex)
+	return corners[hex_vertex_corner(layout.Orientation(), v.direction)]
+}
+
+// Vertex returns the corner of the hex between the given direction and the next one counter-clockwise.
+func (h Hex) Vertex(direction Direction) Vertex {
+	return NewVertex(h, direction)
+}
+
+// Vertices returns the six corners of the hex in direction order.
+func (h Hex) Vertices() (results [6]Vertex) {
+	for direction := 0; direction < 6; direction++ {
+		results[direction] = NewVertex(h, Direction(direction))
+	}
+	return results
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"slices"
+	"testing"
+)
+
+// points_near returns true if the points are within a millionth of a pixel of each other.
+func points_near(a, b Point) bool {
+	return a.Distance(b) < 1e-6
+}
+
+func TestEdgeCanonical(t *testing.T) {
+	for _, h := range NewHexagonGrid[bool](3).Hexes() {
+		for direction := Direction(0); direction < 6; direction++ {
+			e, neighbor := h.Edge(direction), h.Neighbor(direction)
+			if got := neighbor.Edge(direction.Opposite()); got != e {
+				t.Errorf("%v: Edge(%d): want %v from the neighbor: got %v", h, direction, e, got)
+			}
+			if d := e.Direction(); d < 0 || d > 2 {
+				t.Errorf("%v: Edge(%d): direction %d is not canonical", h, direction, d)
+			}
+			if a, b := e.Hexes(); !(a == h && b == neighbor) && !(a == neighbor && b == h) {
+				t.Errorf("%v: Edge(%d).Hexes(): want %v and %v: got %v and %v", h, direction, h, neighbor, a, b)
+			}
+			if got := h.Edges()[direction]; got != e {
+				t.Errorf("%v: Edges()[%d]: want %v: got %v", h, direction, e, got)
+			}
+		}
+	}
+}
+
+func TestVertexCanonical(t *testing.T) {
+	for _, h := range NewHexagonGrid[bool](3).Hexes() {
+		for direction := Direction(0); direction < 6; direction++ {
+			// the vertex is shared by the hex and its neighbors in direction and direction+1,
+			// and each of the three names it in its own directions.
+			v := h.Vertex(direction)
+			a, b := h.Neighbor(direction), h.Neighbor(direction+1)
+			if got := a.Vertex(direction + 2); got != v {
+				t.Errorf("%v: Vertex(%d): want %v from %v: got %v", h, direction, v, a, got)
+			}
+			if got := b.Vertex(direction + 4); got != v {
+				t.Errorf("%v: Vertex(%d): want %v from %v: got %v", h, direction, v, b, got)
+			}
+			if d := v.Direction(); d != 0 && d != 1 {
+				t.Errorf("%v: Vertex(%d): direction %d is not canonical", h, direction, d)
+			}
+			hexes := v.Hexes()
+			for _, want := range []Hex{h, a, b} {
+				if !slices.Contains(hexes[:], want) {
+					t.Errorf("%v: Vertex(%d).Hexes(): want %v in %v", h, direction, want, hexes)
+				}
+			}
+			if got := h.Vertices()[direction]; got != v {
+				t.Errorf("%v: Vertices()[%d]: want %v: got %v", h, direction, v, got)
+			}
+		}
+	}
+}
+
+// TestVertexAgrees checks that the hexes, edges and neighbors of a vertex describe the same corner.
+func TestVertexAgrees(t *testing.T) {
+	for _, h := range NewHexagonGrid[bool](3).Hexes() {
+		for _, v := range h.Vertices() {
+			hexes, edges, neighbors := v.Hexes(), v.Edges(), v.Neighbors()
+			for i, e := range edges {
+				// each edge runs between two of the hexes and ends at the vertex and its neighbor.
+				a, b := e.Hexes()
+				if !slices.Contains(hexes[:], a) || !slices.Contains(hexes[:], b) {
+					t.Errorf("%v: Edges()[%d]: %v is not between %v", v, i, e, hexes)
+				}
+				if x, y := e.Vertices(); !(x == v && y == neighbors[i]) && !(x == neighbors[i] && y == v) {
+					t.Errorf("%v: Edges()[%d]: want ends %v and %v: got %v and %v", v, i, v, neighbors[i], x, y)
+				}
+				// a neighbor shares the two hexes on either side of the edge between them.
+				shared := 0
+				for _, nh := range neighbors[i].Hexes() {
+					if slices.Contains(hexes[:], nh) {
+						shared++
+					}
+				}
+				if shared != 2 {
+					t.Errorf("%v: Neighbors()[%d]: want 2 shared hexes: got %d", v, i, shared)
+				}
+			}
+			if edges[0] == edges[1] || edges[1] == edges[2] || edges[0] == edges[2] {
+				t.Errorf("%v: Edges(): want three edges: got %v", v, edges)
+			}
+		}
+		for _, e := range h.Edges() {
+			neighbors := e.Neighbors()
+			x, y := e.Vertices()
+			for i, n := range neighbors {
+				nx, ny := n.Vertices()
+				if n == e || slices.Index(neighbors[:], n) != i {
+					t.Errorf("%v: Neighbors(): want four other edges: got %v", e, neighbors)
+				} else if nx != x && nx != y && ny != x && ny != y {
+					t.Errorf("%v: Neighbors()[%d]: %v shares no vertex", e, i, n)
+				}
+			}
+		}
+	}
+}
+
+func TestEdgeVertexPoints(t *testing.T) {
+	for name, layout := range test_layouts() {
+		for _, h := range NewHexagonGrid[bool](2).Hexes() {
+			_, corners := layout.Points(h)
+			for direction := Direction(0); direction < 6; direction++ {
+				neighbor := h.Neighbor(direction)
+				_, neighborCorners := layout.Points(neighbor)
+
+				// the ends of an edge are corners of both hexes, and its middle is halfway between their centers.
+				a, b := h.Edge(direction).Points(layout)
+				for _, p := range []Point{a, b} {
+					if !slices.ContainsFunc(corners[:], func(c Point) bool { return points_near(c, p) }) ||
+						!slices.ContainsFunc(neighborCorners[:], func(c Point) bool { return points_near(c, p) }) {
+						t.Errorf("%s: %v: Edge(%d).Points(): %v is not a corner of both hexes", name, h, direction, p)
+					}
+				}
+				middle := layout.HexToCenterPoint(h).Lerp(layout.HexToCenterPoint(neighbor), 0.5)
+				if got := a.Lerp(b, 0.5); !points_near(got, middle) {
+					t.Errorf("%s: %v: Edge(%d).Points(): want middle %v: got %v", name, h, direction, middle, got)
+				}
+
+				// the vertex is a corner of all three hexes that share it, and an end of each of its edges.
+				v := h.Vertex(direction)
+				p := v.Point(layout)
+				for _, vh := range v.Hexes() {
+					_, vc := layout.Points(vh)
+					if !slices.ContainsFunc(vc[:], func(c Point) bool { return points_near(c, p) }) {
+						t.Errorf("%s: %v: Vertex(%d).Point(): %v is not a corner of %v", name, h, direction, p, vh)
+					}
+				}
+				for _, e := range v.Edges() {
+					if x, y := e.Points(layout); !points_near(x, p) && !points_near(y, p) {
+						t.Errorf("%s: %v: Vertex(%d).Point(): %v is not an end of %v", name, h, direction, p, e)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package fog
+
+import (
+	"github.com/playbymail/hexes"
+	"github.com/playbymail/hexes/raster"
+	"image"
+	"image/color"
+)
+
+// DrawOptions controls how a player's view is drawn.
+type DrawOptions[T any] struct {
+	// Options is passed to raster.Draw. Its Fill and Label are replaced.
+	raster.Options
+
+	// Color returns the fill color for a hex the player knows about.
+	// If nil, known hexes are not filled.
+	Color func(h hexes.Hex, value T) color.Color
+
+	// Label returns the label for a hex the player knows about.
+	// If nil, hexes are not labeled. Unknown hexes are never labeled.
+	Label func(h hexes.Hex, value T) string
+
+	// Unknown is the fill color that masks unknown hexes. Nil means dark gray.
+	Unknown color.Color
+
+	// Dim is how far remembered hexes are blended toward the Unknown color,
+	// from 0 (not at all) to 1 (all the way). Zero means 0.5.
+	Dim float64
+}
+
+// Fill returns a fill function for raster.Options that shows the player's view on the turn.
+// Visible hexes use the color, remembered hexes use the color dimmed, and unknown hexes are masked.
+func (k *Knowledge[T]) Fill(turn int, opts DrawOptions[T]) func(h hexes.Hex) color.Color {
+	unknown := opts.Unknown
+	if unknown == nil {
+		unknown = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xff}
+	}
+	dim := opts.Dim
+	if dim == 0 {
+		dim = 0.5
+	}
+	return func(h hexes.Hex) color.Color {
+		o, ok := k.At(turn, h)
+		if !ok {
+			return unknown
+		} else if opts.Color == nil {
+			return nil
+		}
+		c := opts.Color(h, o.Value)
+		if c == nil || o.Turn == turn {
+			return c
+		}
+		return blend(c, unknown, dim)
+	}
+}
+
+// Draw draws the hexes as the player sees them on the turn.
+func (k *Knowledge[T]) Draw(layout hexes.Layout, hv []hexes.Hex, turn int, opts DrawOptions[T]) image.Image {
+	options := opts.Options
+	options.Fill = k.Fill(turn, opts)
+	options.Label = nil
+	if opts.Label != nil {
+		options.Label = func(h hexes.Hex) string {
+			if o, ok := k.At(turn, h); ok {
+				return opts.Label(h, o.Value)
+			}
+			return ""
+		}
+	}
+	return raster.Draw(layout, hv, options)
+}
+
+// blend returns the color t of the way from a to b.
+func blend(a, b color.Color, t float64) color.Color {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	mix := func(x, y uint32) uint16 {
+		return uint16(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA64{R: mix(ar, br), G: mix(ag, bg), B: mix(ab, bb), A: mix(aa, ba)}
+}
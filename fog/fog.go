@@ -0,0 +1,184 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package fog tracks what each player knows about the map.
+//
+// A player's knowledge is a history of observations for each hex. An
+// observation records what the player saw and the turn they saw it. Hexes
+// observed on the current turn are visible, hexes observed on an earlier
+// turn are remembered, and the rest are unknown.
+package fog
+
+import (
+	"github.com/playbymail/hexes"
+	"sort"
+)
+
+// State is what a player knows about a hex on a turn.
+type State int
+
+const (
+	Unknown    State = iota // never observed
+	Remembered              // observed on an earlier turn
+	Visible                 // observed on the current turn
+)
+
+func (s State) String() string {
+	switch s {
+	case Unknown:
+		return "unknown"
+	case Remembered:
+		return "remembered"
+	case Visible:
+		return "visible"
+	}
+	return "State(?)"
+}
+
+// Observation is what was seen in a hex on a turn.
+type Observation[T any] struct {
+	Turn  int
+	Value T
+}
+
+// Knowledge is one player's observations.
+type Knowledge[T any] struct {
+	limit   int
+	history map[hexes.Hex][]Observation[T] // oldest first, at most one per turn
+}
+
+// NewKnowledge returns an empty set of observations.
+// Each hex keeps its last limit observations. Zero means no limit.
+func NewKnowledge[T any](limit int) *Knowledge[T] {
+	return &Knowledge[T]{
+		limit:   limit,
+		history: make(map[hexes.Hex][]Observation[T]),
+	}
+}
+
+// Observe records what was seen in the hex on the turn.
+// A second observation on the same turn replaces the first.
+// Observations may arrive out of order; they are kept sorted by turn.
+func (k *Knowledge[T]) Observe(turn int, h hexes.Hex, value T) {
+	history := k.history[h]
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].Turn >= turn
+	})
+	if i < len(history) && history[i].Turn == turn {
+		history[i].Value = value
+		return
+	}
+	history = append(history, Observation[T]{})
+	copy(history[i+1:], history[i:])
+	history[i] = Observation[T]{Turn: turn, Value: value}
+	if k.limit != 0 && len(history) > k.limit {
+		history = history[len(history)-k.limit:]
+	}
+	k.history[h] = history
+}
+
+// ObserveAll records what was seen in each of the hexes on the turn.
+func (k *Knowledge[T]) ObserveAll(turn int, hv []hexes.Hex, value func(h hexes.Hex) T) {
+	for _, h := range hv {
+		k.Observe(turn, h, value(h))
+	}
+}
+
+// Merge adds all of the other player's observations to this one.
+// When both have observed a hex on the same turn, the other player's observation wins.
+func (k *Knowledge[T]) Merge(other *Knowledge[T]) {
+	for h, history := range other.history {
+		for _, o := range history {
+			k.Observe(o.Turn, h, o.Value)
+		}
+	}
+}
+
+// LastSeen returns the most recent observation of the hex.
+func (k *Knowledge[T]) LastSeen(h hexes.Hex) (Observation[T], bool) {
+	history := k.history[h]
+	if len(history) == 0 {
+		return Observation[T]{}, false
+	}
+	return history[len(history)-1], true
+}
+
+// History returns the observations of the hex, oldest first.
+func (k *Knowledge[T]) History(h hexes.Hex) []Observation[T] {
+	return append([]Observation[T](nil), k.history[h]...)
+}
+
+// State returns what the player knows about the hex on the turn.
+// Observations made after the turn are ignored.
+func (k *Knowledge[T]) State(turn int, h hexes.Hex) State {
+	if o, ok := k.At(turn, h); !ok {
+		return Unknown
+	} else if o.Turn == turn {
+		return Visible
+	}
+	return Remembered
+}
+
+// At returns the most recent observation of the hex made on or before the turn.
+func (k *Knowledge[T]) At(turn int, h hexes.Hex) (Observation[T], bool) {
+	history := k.history[h]
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].Turn > turn
+	})
+	if i == 0 {
+		return Observation[T]{}, false
+	}
+	return history[i-1], true
+}
+
+// Hexes returns the hexes that have been observed, sorted by r, then q.
+func (k *Knowledge[T]) Hexes() []hexes.Hex {
+	rg := hexes.NewRegion()
+	for h := range k.history {
+		rg.Add(h)
+	}
+	return rg.Hexes()
+}
+
+// Visible returns the hexes that are visible on the turn.
+func (k *Knowledge[T]) Visible(turn int) (results []hexes.Hex) {
+	for _, h := range k.Hexes() {
+		if k.State(turn, h) == Visible {
+			results = append(results, h)
+		}
+	}
+	return results
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// players
+
+// Players holds the knowledge of every player.
+type Players[T any] struct {
+	limit   int
+	players map[string]*Knowledge[T]
+}
+
+// NewPlayers returns an empty set of players. The limit is passed to NewKnowledge.
+func NewPlayers[T any](limit int) *Players[T] {
+	return &Players[T]{limit: limit, players: make(map[string]*Knowledge[T])}
+}
+
+// Player returns the player's knowledge, creating it if needed.
+func (p *Players[T]) Player(name string) *Knowledge[T] {
+	k, ok := p.players[name]
+	if !ok {
+		k = NewKnowledge[T](p.limit)
+		p.players[name] = k
+	}
+	return k
+}
+
+// Names returns the names of the players, sorted.
+func (p *Players[T]) Names() []string {
+	names := make([]string, 0, len(p.players))
+	for name := range p.players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
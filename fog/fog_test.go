@@ -0,0 +1,172 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package fog
+
+import (
+	"github.com/playbymail/hexes"
+	"slices"
+	"testing"
+)
+
+// turns returns the turns of the observations.
+func turns[T any](history []Observation[T]) (results []int) {
+	for _, o := range history {
+		results = append(results, o.Turn)
+	}
+	return results
+}
+
+func TestState(t *testing.T) {
+	h, other := hexes.NewHex(1, -1, 0), hexes.NewHex(0, 0, 0)
+	k := NewKnowledge[string](0)
+	k.Observe(3, h, "forest")
+	k.Observe(6, h, "burnt")
+	for _, tc := range []struct {
+		id    int
+		turn  int
+		h     hexes.Hex
+		want  State
+		value string
+	}{
+		{1, 2, h, Unknown, ""},
+		{2, 3, h, Visible, "forest"},
+		{3, 4, h, Remembered, "forest"},
+		{4, 5, h, Remembered, "forest"},
+		{5, 6, h, Visible, "burnt"},
+		{6, 9, h, Remembered, "burnt"},
+		{7, 6, other, Unknown, ""},
+	} {
+		if got := k.State(tc.turn, tc.h); got != tc.want {
+			t.Errorf("%d: State(%d, %v): want %v: got %v", tc.id, tc.turn, tc.h, tc.want, got)
+		}
+		o, ok := k.At(tc.turn, tc.h)
+		if ok != (tc.want != Unknown) || o.Value != tc.value {
+			t.Errorf("%d: At(%d, %v): want %q: got %q, %v", tc.id, tc.turn, tc.h, tc.value, o.Value, ok)
+		}
+	}
+
+	if got := k.Visible(6); !slices.Equal(got, []hexes.Hex{h}) {
+		t.Errorf("Visible(6): want [%v]: got %v", h, got)
+	}
+	if got := k.Visible(5); got != nil {
+		t.Errorf("Visible(5): want nil: got %v", got)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	h := hexes.NewHex(2, -3, 1)
+	k := NewKnowledge[string](0)
+	// observations arrive out of order and the second one on turn 3 replaces the first.
+	k.Observe(5, h, "e")
+	k.Observe(1, h, "a")
+	k.Observe(3, h, "c")
+	k.Observe(3, h, "C")
+	k.Observe(4, h, "d")
+
+	history := k.History(h)
+	if got, want := turns(history), []int{1, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("History: want turns %v: got %v", want, got)
+	}
+	if history[1].Value != "C" {
+		t.Errorf("History: want the replaced value %q on turn 3: got %q", "C", history[1].Value)
+	}
+	if o, ok := k.LastSeen(h); !ok || o.Turn != 5 || o.Value != "e" {
+		t.Errorf("LastSeen: want turn 5: got %v, %v", o, ok)
+	}
+
+	// History returns a copy.
+	history[0].Value = "changed"
+	if got := k.History(h)[0].Value; got != "a" {
+		t.Errorf("History: want a copy: got %q", got)
+	}
+
+	if _, ok := k.LastSeen(hexes.NewHex(0, 0, 0)); ok {
+		t.Errorf("LastSeen: want nothing for an unobserved hex")
+	}
+
+	k.ObserveAll(7, []hexes.Hex{h, hexes.NewHex(0, 0, 0)}, func(h hexes.Hex) string { return h.String() })
+	if got, want := k.Hexes(), []hexes.Hex{h, hexes.NewHex(0, 0, 0)}; !slices.Equal(got, want) {
+		t.Errorf("Hexes: want %v: got %v", want, got)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	h := hexes.NewHex(0, 1, -1)
+	k := NewKnowledge[int](2)
+	for _, tc := range []struct {
+		id   int
+		turn int
+		want []int // the turns kept
+	}{
+		{1, 1, []int{1}},
+		{2, 2, []int{1, 2}},
+		{3, 3, []int{2, 3}},
+		{4, 1, []int{2, 3}}, // older than everything kept, so it is dropped
+		{5, 3, []int{2, 3}}, // replaces the observation on turn 3
+		{6, 5, []int{3, 5}},
+		{7, 4, []int{4, 5}},
+	} {
+		k.Observe(tc.turn, h, 10*tc.id)
+		if got := turns(k.History(h)); !slices.Equal(got, tc.want) {
+			t.Errorf("%d: Observe(%d): want turns %v: got %v", tc.id, tc.turn, tc.want, got)
+		}
+	}
+
+	// forgotten turns are unknown, even though the hex was seen then.
+	if got := k.State(2, h); got != Unknown {
+		t.Errorf("State(2): want %v: got %v", Unknown, got)
+	}
+	if got := k.State(4, h); got != Visible {
+		t.Errorf("State(4): want %v: got %v", Visible, got)
+	}
+
+	unlimited := NewKnowledge[int](0)
+	for turn := 1; turn <= 50; turn++ {
+		unlimited.Observe(turn, h, turn)
+	}
+	if got := len(unlimited.History(h)); got != 50 {
+		t.Errorf("no limit: want 50 observations: got %d", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := hexes.NewHex(0, 0, 0), hexes.NewHex(1, 0, -1)
+	k, other := NewKnowledge[string](0), NewKnowledge[string](0)
+	k.Observe(1, a, "mine")
+	k.Observe(2, a, "mine")
+	other.Observe(2, a, "theirs")
+	other.Observe(3, a, "theirs")
+	other.Observe(1, b, "theirs")
+
+	k.Merge(other)
+	if got := turns(k.History(a)); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Merge: want turns [1 2 3]: got %v", got)
+	}
+	// the other player's observation wins on the same turn.
+	if o, _ := k.At(2, a); o.Value != "theirs" {
+		t.Errorf("Merge: want %q on turn 2: got %q", "theirs", o.Value)
+	}
+	if o, _ := k.At(1, a); o.Value != "mine" {
+		t.Errorf("Merge: want %q on turn 1: got %q", "mine", o.Value)
+	}
+	if got := k.State(1, b); got != Visible {
+		t.Errorf("Merge: want %v: got %v", Visible, got)
+	}
+	if got := turns(other.History(b)); !slices.Equal(got, []int{1}) {
+		t.Errorf("Merge: changed the other player: got %v", got)
+	}
+}
+
+func TestPlayers(t *testing.T) {
+	p := NewPlayers[int](1)
+	p.Player("bob").Observe(1, hexes.NewHex(0, 0, 0), 1)
+	p.Player("alice").Observe(2, hexes.NewHex(0, 0, 0), 2)
+	p.Player("bob").Observe(3, hexes.NewHex(0, 0, 0), 3)
+	if got := p.Names(); !slices.Equal(got, []string{"alice", "bob"}) {
+		t.Errorf("Names: want [alice bob]: got %v", got)
+	}
+	// the limit is passed to each player.
+	if got := turns(p.Player("bob").History(hexes.NewHex(0, 0, 0))); !slices.Equal(got, []int{3}) {
+		t.Errorf("bob: want turns [3]: got %v", got)
+	}
+}
@@ -5,9 +5,7 @@ go 1.22.6
 require (
 	github.com/fogleman/gg v1.3.0
 	github.com/mdhender/semver v0.0.0-20240121182447-31da48bf9537
+	golang.org/x/image v0.19.0
 )
 
-require (
-	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
-	golang.org/x/image v0.19.0 // indirect
-)
+require github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
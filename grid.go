@@ -0,0 +1,180 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+// --------------------------------------------------------------------------------------------------------------------
+// grids
+//
+// A Grid is a bounded set of hexes with an optional value stored in each hex.
+// The bounds are fixed when the grid is created. Hexes are returned in the
+// order that the shape constructor generated them.
+
+type Grid[T any] struct {
+	hexes  []Hex
+	index  map[Hex]int
+	values map[Hex]T
+}
+
+// NewGrid returns a grid bounded by the given hexes. Duplicates are ignored.
+func NewGrid[T any](hexes ...Hex) *Grid[T] {
+	g := &Grid[T]{
+		index:  make(map[Hex]int, len(hexes)),
+		values: make(map[Hex]T),
+	}
+	for _, h := range hexes {
+		if _, ok := g.index[h]; ok {
+			continue
+		}
+		g.index[h] = len(g.hexes)
+		g.hexes = append(g.hexes, h)
+	}
+	return g
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// map shapes
+
+// NewHexagonGrid returns a hexagon shaped grid centered on the origin.
+// A radius of 0 is a single hex.
+func NewHexagonGrid[T any](radius int) *Grid[T] {
+	return NewGrid[T](hexagon_shape(radius)...)
+}
+
+// NewRectangleGrid returns a grid covering offset columns 0 to columns-1 and rows 0 to rows-1
+// of the layout. Hexes are ordered by column and then by row.
+func NewRectangleGrid[T any](layout Layout, columns, rows int) *Grid[T] {
+	return NewGrid[T](rectangle_shape(layout, columns, rows)...)
+}
+
+// NewParallelogramGrid returns a grid covering q1 to q2 and r1 to r2, inclusive.
+func NewParallelogramGrid[T any](q1, q2, r1, r2 int) *Grid[T] {
+	return NewGrid[T](parallelogram_shape(q1, q2, r1, r2)...)
+}
+
+// NewTriangleGrid returns a triangle shaped grid with one corner at the origin.
+// Each side of the triangle has size+1 hexes.
+func NewTriangleGrid[T any](size int) *Grid[T] {
+	return NewGrid[T](triangle_shape(size)...)
+}
+
+func hexagon_shape(radius int) (results []Hex) {
+	for q := -radius; q <= radius; q++ {
+		r1, r2 := max(-radius, -q-radius), min(radius, -q+radius)
+		for r := r1; r <= r2; r++ {
+			results = append(results, Hex{q: q, r: r, s: -q - r})
+		}
+	}
+	return results
+}
+
+func rectangle_shape(layout Layout, columns, rows int) (results []Hex) {
+	for column := 0; column < columns; column++ {
+		for row := 0; row < rows; row++ {
+			results = append(results, layout.OffsetToHex(column, row))
+		}
+	}
+	return results
+}
+
+func parallelogram_shape(q1, q2, r1, r2 int) (results []Hex) {
+	for q := q1; q <= q2; q++ {
+		for r := r1; r <= r2; r++ {
+			results = append(results, Hex{q: q, r: r, s: -q - r})
+		}
+	}
+	return results
+}
+
+func triangle_shape(size int) (results []Hex) {
+	for q := 0; q <= size; q++ {
+		for r := 0; r <= size-q; r++ {
+			results = append(results, Hex{q: q, r: r, s: -q - r})
+		}
+	}
+	return results
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// bounds
+
+// Contains returns true if the hex is inside the bounds of the grid.
+func (g *Grid[T]) Contains(h Hex) bool {
+	_, ok := g.index[h]
+	return ok
+}
+
+// Len returns the number of hexes inside the bounds of the grid.
+func (g *Grid[T]) Len() int {
+	return len(g.hexes)
+}
+
+// Hexes returns a copy of the hexes in the grid, in order.
+func (g *Grid[T]) Hexes() []Hex {
+	return append([]Hex(nil), g.hexes...)
+}
+
+// Neighbors returns the neighbors of the hex that are inside the bounds of the grid.
+// The neighbors are returned in direction order.
+func (g *Grid[T]) Neighbors(h Hex) (results []Hex) {
+	for direction := 0; direction < 6; direction++ {
+		if neighbor := hex_neighbor(h, direction); g.Contains(neighbor) {
+			results = append(results, neighbor)
+		}
+	}
+	return results
+}
+
+// Clip returns the hexes that are inside the bounds of the grid.
+// The hexes are returned in the same order as the input.
+func (g *Grid[T]) Clip(hexes []Hex) (results []Hex) {
+	for _, h := range hexes {
+		if g.Contains(h) {
+			results = append(results, h)
+		}
+	}
+	return results
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// values
+
+// Get returns the value stored in the hex.
+// It returns false if no value has been stored.
+func (g *Grid[T]) Get(h Hex) (T, bool) {
+	v, ok := g.values[h]
+	return v, ok
+}
+
+// Set stores the value in the hex.
+// It returns false if the hex is outside the bounds of the grid.
+func (g *Grid[T]) Set(h Hex, v T) bool {
+	if !g.Contains(h) {
+		return false
+	}
+	g.values[h] = v
+	return true
+}
+
+// Has returns true if a value has been stored in the hex.
+func (g *Grid[T]) Has(h Hex) bool {
+	_, ok := g.values[h]
+	return ok
+}
+
+// Delete removes the value stored in the hex.
+// The hex stays inside the bounds of the grid.
+func (g *Grid[T]) Delete(h Hex) {
+	delete(g.values, h)
+}
+
+// Range calls fn for every hex that has a value, in grid order.
+// It stops if fn returns false.
+func (g *Grid[T]) Range(fn func(h Hex, v T) bool) {
+	for _, h := range g.hexes {
+		if v, ok := g.values[h]; ok {
+			if !fn(h, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGridShapes(t *testing.T) {
+	layout := NewPointyEvenLayout(NewPoint(1, 1), NewPoint(0, 0))
+	for _, tc := range []struct {
+		id     int
+		grid   *Grid[int]
+		want   int            // the number of hexes
+		inside func(Hex) bool // the hexes that belong to the shape
+	}{
+		{1, NewHexagonGrid[int](0), 1, func(h Hex) bool { return h.Length() == 0 }},
+		{2, NewHexagonGrid[int](3), 37, func(h Hex) bool { return h.Length() <= 3 }},
+		{3, NewRectangleGrid[int](layout, 5, 3), 15, func(h Hex) bool {
+			column, row := layout.HexToOffset(h)
+			return 0 <= column && column < 5 && 0 <= row && row < 3
+		}},
+		{4, NewRectangleGrid[int](layout, 0, 3), 0, func(h Hex) bool { return false }},
+		{5, NewParallelogramGrid[int](-2, 1, 0, 2), 12, func(h Hex) bool {
+			return -2 <= h.q && h.q <= 1 && 0 <= h.r && h.r <= 2
+		}},
+		{6, NewTriangleGrid[int](0), 1, func(h Hex) bool { return h.Length() == 0 }},
+		{7, NewTriangleGrid[int](4), 15, func(h Hex) bool { return h.q >= 0 && h.r >= 0 && h.q+h.r <= 4 }},
+	} {
+		if tc.grid.Len() != tc.want || len(tc.grid.Hexes()) != tc.want {
+			t.Errorf("%d: want %d hexes: got %d", tc.id, tc.want, tc.grid.Len())
+		}
+		for _, h := range tc.grid.Hexes() {
+			if !tc.inside(h) {
+				t.Errorf("%d: %v: not in the shape", tc.id, h)
+			}
+		}
+		for _, h := range hexagon_shape(12) {
+			if tc.inside(h) != tc.grid.Contains(h) {
+				t.Errorf("%d: Contains(%v): want %v", tc.id, h, tc.inside(h))
+			}
+		}
+	}
+}
+
+func TestNewGrid(t *testing.T) {
+	a, b, c := NewHex(0, 0, 0), NewHex(1, -1, 0), NewHex(-1, 1, 0)
+	g := NewGrid[bool](b, a, b, c, a)
+	if got, want := g.Hexes(), []Hex{b, a, c}; !slices.Equal(got, want) {
+		t.Errorf("want %v: got %v", want, got)
+	}
+	// Hexes returns a copy
+	g.Hexes()[0] = NewHex(9, 9, -18)
+	if g.Hexes()[0] != b {
+		t.Errorf("Hexes: want a copy")
+	}
+}
+
+func TestGridNeighbors(t *testing.T) {
+	g := NewHexagonGrid[bool](2)
+	for _, tc := range []struct {
+		id   int
+		h    Hex
+		want int
+	}{
+		{1, NewHex(0, 0, 0), 6},
+		{2, NewHex(1, -1, 0), 6},
+		{3, NewHex(2, -1, -1), 4}, // on a side
+		{4, NewHex(2, 0, -2), 3},  // on a corner
+		{5, NewHex(3, 0, -3), 1},  // outside, next to a corner
+	} {
+		got := g.Neighbors(tc.h)
+		if len(got) != tc.want {
+			t.Errorf("%d: Neighbors(%v): want %d: got %v", tc.id, tc.h, tc.want, got)
+		}
+		for _, neighbor := range got {
+			if !g.Contains(neighbor) || neighbor.DistanceTo(tc.h) != 1 {
+				t.Errorf("%d: Neighbors(%v): %v isn't a neighbor in the grid", tc.id, tc.h, neighbor)
+			}
+		}
+	}
+}
+
+func TestGridClip(t *testing.T) {
+	g := NewTriangleGrid[bool](2)
+	in := []Hex{NewHex(2, 0, -2), NewHex(-1, 0, 1), NewHex(0, 0, 0), NewHex(2, 1, -3), NewHex(0, 2, -2)}
+	if got, want := g.Clip(in), []Hex{NewHex(2, 0, -2), NewHex(0, 0, 0), NewHex(0, 2, -2)}; !slices.Equal(got, want) {
+		t.Errorf("Clip: want %v: got %v", want, got)
+	}
+	if got := g.Clip(nil); got != nil {
+		t.Errorf("Clip(nil): want nil: got %v", got)
+	}
+}
+
+func TestGridValues(t *testing.T) {
+	g := NewHexagonGrid[string](1)
+	a, b, outside := NewHex(1, -1, 0), NewHex(-1, 0, 1), NewHex(2, -2, 0)
+
+	if _, ok := g.Get(a); ok || g.Has(a) {
+		t.Errorf("empty grid: want no value")
+	}
+	if !g.Set(a, "a") || !g.Set(b, "b") {
+		t.Errorf("Set: want true inside the grid")
+	}
+	if g.Set(outside, "x") || g.Has(outside) {
+		t.Errorf("Set: want false outside the grid")
+	}
+	if v, ok := g.Get(a); !ok || v != "a" || !g.Has(a) {
+		t.Errorf("Get(%v): want a: got %q, %v", a, v, ok)
+	}
+
+	// Range visits the hexes with values in grid order, which is by q and then r
+	var got []Hex
+	g.Range(func(h Hex, v string) bool {
+		got = append(got, h)
+		return true
+	})
+	if want := []Hex{b, a}; !slices.Equal(got, want) {
+		t.Errorf("Range: want %v: got %v", want, got)
+	}
+	got = nil
+	g.Range(func(h Hex, v string) bool {
+		got = append(got, h)
+		return false
+	})
+	if len(got) != 1 {
+		t.Errorf("Range: want to stop after 1: got %v", got)
+	}
+
+	g.Delete(a)
+	if _, ok := g.Get(a); ok || g.Has(a) || !g.Contains(a) {
+		t.Errorf("Delete: want no value, but still in bounds")
+	}
+}
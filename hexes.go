@@ -18,6 +18,21 @@ func NewHex(q, r, s int) Hex {
 	return Hex{q: q, r: r, s: s}
 }
 
+// Q returns the q coordinate of the hex.
+func (h Hex) Q() int {
+	return h.q
+}
+
+// R returns the r coordinate of the hex.
+func (h Hex) R() int {
+	return h.r
+}
+
+// S returns the s coordinate of the hex.
+func (h Hex) S() int {
+	return h.s
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // equality
 
@@ -29,6 +44,11 @@ func notEquals(a, b Hex) bool {
 	return !equals(a, b)
 }
 
+// Equal returns true if both hexes have the same coordinates.
+func (h Hex) Equal(b Hex) bool {
+	return equals(h, b)
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // arithmetic
 
@@ -44,6 +64,21 @@ func hex_multiply(a Hex, k int) Hex {
 	return Hex{q: a.q * k, r: a.r * k, s: a.s * k}
 }
 
+// Add returns the sum of the two hexes.
+func (h Hex) Add(b Hex) Hex {
+	return hex_add(h, b)
+}
+
+// Sub returns the difference of the two hexes.
+func (h Hex) Sub(b Hex) Hex {
+	return hex_subtract(h, b)
+}
+
+// Scale returns the hex multiplied by k.
+func (h Hex) Scale(k int) Hex {
+	return hex_multiply(h, k)
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // distance
 
@@ -63,6 +98,16 @@ func hex_distance(a, b Hex) int {
 	return hex_length(hex_subtract(a, b))
 }
 
+// Length returns the number of steps from the origin to the hex.
+func (h Hex) Length() int {
+	return hex_length(h)
+}
+
+// DistanceTo returns the number of steps between the two hexes.
+func (h Hex) DistanceTo(b Hex) int {
+	return hex_distance(h, b)
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // neighbors
 
@@ -84,6 +129,33 @@ func hex_neighbor(hex Hex, direction int) Hex {
 	return hex_add(hex, hex_direction(direction))
 }
 
+// hex_diagonals uses the same ordering as hex_directions.
+// Diagonal i is between directions i and i+1.
+var hex_diagonals = [6]Hex{
+	{q: 2, r: -1, s: -1},
+	{q: 1, r: -2, s: 1},
+	{q: -1, r: -1, s: 2},
+	{q: -2, r: 1, s: 1},
+	{q: -1, r: 2, s: -1},
+	{q: 1, r: 1, s: -2},
+}
+
+func hex_diagonal_neighbor(hex Hex, direction int) Hex {
+	return hex_add(hex, hex_diagonals[(6+(direction%6))%6])
+}
+
+// Neighbor returns the adjacent hex in the given direction.
+// Directions are counter-clockwise and wrap, so 6 is the same as 0 and -1 is the same as 5.
+func (h Hex) Neighbor(direction Direction) Hex {
+	return hex_neighbor(h, int(direction))
+}
+
+// DiagonalNeighbor returns the hex two steps away that lies between
+// the given direction and the next one counter-clockwise.
+func (h Hex) DiagonalNeighbor(direction Direction) Hex {
+	return hex_diagonal_neighbor(h, int(direction))
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // layout
 
@@ -124,209 +196,222 @@ var (
 	}
 )
 
+// Flat returns the orientation for hexes with flat tops.
+func Flat() Orientation {
+	return layout_flat
+}
+
+// Pointy returns the orientation for hexes with pointy tops.
+func Pointy() Orientation {
+	return layout_pointy
+}
+
+// IsFlat returns true if the orientation is for flat topped hexes.
+func (o Orientation) IsFlat() bool {
+	return o.start_angle == 0
+}
+
+// IsPointy returns true if the orientation is for pointy topped hexes.
+func (o Orientation) IsPointy() bool {
+	return !o.IsFlat()
+}
+
+// Parity selects which columns (flat hexes) or rows (pointy hexes)
+// are pushed when converting to offset coordinates.
+type Parity int
+
+const (
+	Even Parity = iota
+	Odd
+)
+
+func (p Parity) String() string {
+	switch p {
+	case Even:
+		return "even"
+	case Odd:
+		return "odd"
+	}
+	return fmt.Sprintf("Parity(%d)", int(p))
+}
+
+// Layout converts between hex coordinates, offset coordinates, and screen coordinates.
 type Layout interface {
+	Orientation() Orientation
+	Parity() Parity
+	Size() Point
+	Origin() Point
+
 	// screen conversions
-	hex_to_pixel(h Hex) Point
-	pixel_to_hex(p Point) FractionalHex
+	HexToCenterPoint(h Hex) Point
+	PixelToFractionalHex(p Point) FractionalHex
+	PixelToHex(p Point) Hex
 
 	// drawing
-	hex_corner_offset(corner int) Point
-	polygon_corners(h Hex) [6]Point
-
-	HexToCenterPoint(h Hex) Point
 	Points(h Hex) (Point, [6]Point)
 
+	// offset coordinates
 	HexToOffset(h Hex) (column, row int)
 	OffsetToHex(column, row int) Hex
 }
 
-type FlatEvenLayout struct {
-	size   Point
-	origin Point
+// hexLayout is the implementation shared by every layout.
+type hexLayout struct {
+	orientation Orientation
+	parity      Parity
+	size        Point
+	origin      Point
 }
 
-func NewFlatEvenLayout(size, origin Point) Layout {
-	return &FlatEvenLayout{
-		size:   size,
-		origin: origin,
-	}
+// FlatEvenLayout is a layout for flat hexes using even-q offset coordinates.
+type FlatEvenLayout struct {
+	hexLayout
 }
 
+// FlatOddLayout is a layout for flat hexes using odd-q offset coordinates.
 type FlatOddLayout struct {
-	size   Point
-	origin Point
+	hexLayout
 }
 
-func NewFlatOddLayout(size, origin Point) Layout {
-	return &FlatOddLayout{
-		size:   size,
-		origin: origin,
-	}
+// PointyEvenLayout is a layout for pointy hexes using even-r offset coordinates.
+type PointyEvenLayout struct {
+	hexLayout
 }
 
-type PointyEvenLayout struct {
-	size   Point
-	origin Point
+// PointyOddLayout is a layout for pointy hexes using odd-r offset coordinates.
+type PointyOddLayout struct {
+	hexLayout
 }
 
-func NewPointyEvenLayout(size, origin Point) Layout {
-	return &PointyEvenLayout{
-		size:   size,
-		origin: origin,
+// NewLayout returns a layout for the orientation and offset parity.
+// The size is the distance from the center of a hex to its corners
+// and the origin is the screen coordinate of the center of hex (0, 0, 0).
+func NewLayout(orientation Orientation, parity Parity, size, origin Point) Layout {
+	layout := hexLayout{
+		orientation: orientation,
+		parity:      parity,
+		size:        size,
+		origin:      origin,
 	}
+	switch {
+	case orientation.IsFlat() && parity == Even:
+		return &FlatEvenLayout{layout}
+	case orientation.IsFlat():
+		return &FlatOddLayout{layout}
+	case parity == Even:
+		return &PointyEvenLayout{layout}
+	}
+	return &PointyOddLayout{layout}
 }
 
-type Point struct {
-	X, Y float64
+// NewFlatEvenLayout returns a layout for flat hexes using even-q offset coordinates.
+func NewFlatEvenLayout(size, origin Point) Layout {
+	return NewLayout(layout_flat, Even, size, origin)
 }
 
-func (p Point) String() string {
-	return fmt.Sprintf("(%f, %f)", p.X, p.Y)
+// NewFlatOddLayout returns a layout for flat hexes using odd-q offset coordinates.
+func NewFlatOddLayout(size, origin Point) Layout {
+	return NewLayout(layout_flat, Odd, size, origin)
 }
 
-func NewPoint(x, y float64) Point {
-	return Point{X: x, Y: y}
+// NewPointyEvenLayout returns a layout for pointy hexes using even-r offset coordinates.
+func NewPointyEvenLayout(size, origin Point) Layout {
+	return NewLayout(layout_pointy, Even, size, origin)
 }
 
-// --------------------------------------------------------------------------------------------------------------------
-// hex to screen
+// NewPointyOddLayout returns a layout for pointy hexes using odd-r offset coordinates.
+func NewPointyOddLayout(size, origin Point) Layout {
+	return NewLayout(layout_pointy, Odd, size, origin)
+}
 
-func (layout *FlatEvenLayout) hex_to_pixel(h Hex) Point {
-	var x = (layout_flat.f0*float64(h.q) + layout_flat.f1*float64(h.r)) * layout.size.X
-	var y = (layout_flat.f2*float64(h.q) + layout_flat.f3*float64(h.r)) * layout.size.Y
-	return Point{
-		X: x + layout.origin.X,
-		Y: y + layout.origin.Y,
-	}
+func (layout *hexLayout) Orientation() Orientation {
+	return layout.orientation
 }
 
-func (layout *FlatEvenLayout) HexToCenterPoint(h Hex) Point {
-	return layout.hex_to_pixel(h)
+func (layout *hexLayout) Parity() Parity {
+	return layout.parity
 }
 
-func (layout *FlatOddLayout) hex_to_pixel(h Hex) Point {
-	var x = (layout_flat.f0*float64(h.q) + layout_flat.f1*float64(h.r)) * layout.size.X
-	var y = (layout_flat.f2*float64(h.q) + layout_flat.f3*float64(h.r)) * layout.size.Y
-	return Point{
-		X: x + layout.origin.X,
-		Y: y + layout.origin.Y,
-	}
+func (layout *hexLayout) Size() Point {
+	return layout.size
 }
 
-func (layout *FlatOddLayout) HexToCenterPoint(h Hex) Point {
-	return layout.hex_to_pixel(h)
+func (layout *hexLayout) Origin() Point {
+	return layout.origin
 }
 
-func (layout *PointyEvenLayout) hex_to_pixel(h Hex) Point {
-	var x = (layout_pointy.f0*float64(h.q) + layout_pointy.f1*float64(h.r)) * layout.size.X
-	var y = (layout_pointy.f2*float64(h.q) + layout_pointy.f3*float64(h.r)) * layout.size.Y
+// --------------------------------------------------------------------------------------------------------------------
+// hex to screen
+
+// hex_to_pixel converts the hex to screen coordinates.
+// The explicit float64 conversions round every product. Without them the
+// compiler may fuse a multiply and an add into a single instruction on some
+// platforms, which gives slightly different results on different machines.
+func (layout *hexLayout) hex_to_pixel(h Hex) Point {
+	var M = layout.orientation
+	var q, r = float64(h.q), float64(h.r)
+	var x = float64(M.f0*q) + float64(M.f1*r)
+	var y = float64(M.f2*q) + float64(M.f3*r)
 	return Point{
-		X: x + layout.origin.X,
-		Y: y + layout.origin.Y,
+		X: float64(x*layout.size.X) + layout.origin.X,
+		Y: float64(y*layout.size.Y) + layout.origin.Y,
 	}
 }
 
-func (layout *PointyEvenLayout) HexToCenterPoint(h Hex) Point {
+func (layout *hexLayout) HexToCenterPoint(h Hex) Point {
 	return layout.hex_to_pixel(h)
 }
 
 // --------------------------------------------------------------------------------------------------------------------
 // screen to hex
 
-func (layout *FlatEvenLayout) pixel_to_hex(p Point) FractionalHex {
-	var M = layout_flat
-	var pt = Point{X: (p.X - layout.origin.X) / layout.size.X, Y: (p.X - layout.origin.Y) / layout.size.Y}
+func (layout *hexLayout) pixel_to_hex(p Point) FractionalHex {
+	var M = layout.orientation
+	var pt = Point{X: (p.X - layout.origin.X) / layout.size.X, Y: (p.Y - layout.origin.Y) / layout.size.Y}
 	var q = M.b0*pt.X + M.b1*pt.Y
 	var r = M.b2*pt.X + M.b3*pt.Y
 	return FractionalHex{q: q, r: r, s: -q - r}
 }
 
-func (layout *FlatOddLayout) pixel_to_hex(p Point) FractionalHex {
-	var M = layout_flat
-	var pt = Point{X: (p.X - layout.origin.X) / layout.size.X, Y: (p.X - layout.origin.Y) / layout.size.Y}
-	var q = M.b0*pt.X + M.b1*pt.Y
-	var r = M.b2*pt.X + M.b3*pt.Y
-	return FractionalHex{q: q, r: r, s: -q - r}
+func (layout *hexLayout) PixelToFractionalHex(p Point) FractionalHex {
+	return layout.pixel_to_hex(p)
 }
 
-func (layout *PointyEvenLayout) pixel_to_hex(p Point) FractionalHex {
-	var M = layout_pointy
-	var pt = Point{X: (p.X - layout.origin.X) / layout.size.X, Y: (p.Y - layout.origin.Y) / layout.size.Y}
-	var q = M.b0*pt.X + M.b1*pt.Y
-	var r = M.b2*pt.X + M.b3*pt.Y
-	return FractionalHex{q: q, r: r, s: -q - r}
+func (layout *hexLayout) PixelToHex(p Point) Hex {
+	return hex_round(layout.pixel_to_hex(p))
 }
 
 // --------------------------------------------------------------------------------------------------------------------
 // drawing hex on screen
 
-func (layout *FlatEvenLayout) hex_corner_offset(corner int) Point {
-	size := layout.size
-	angle := 2.0 * math.Pi * (layout_flat.start_angle + float64(corner)) / 6
-	return Point{X: size.X * math.Cos(angle), Y: size.Y * math.Sin(angle)}
-}
-
-func (layout *FlatOddLayout) hex_corner_offset(corner int) Point {
-	size := layout.size
-	angle := 2.0 * math.Pi * (layout_flat.start_angle + float64(corner)) / 6
-	return Point{X: size.X * math.Cos(angle), Y: size.Y * math.Sin(angle)}
-}
-
-func (layout *PointyEvenLayout) hex_corner_offset(corner int) Point {
+func (layout *hexLayout) hex_corner_offset(corner int) Point {
 	size := layout.size
-	angle := 2.0 * math.Pi * (layout_pointy.start_angle + float64(corner)) / 6
+	angle := 2.0 * math.Pi * (layout.orientation.start_angle + float64(corner)) / 6
 	return Point{X: size.X * math.Cos(angle), Y: size.Y * math.Sin(angle)}
 }
 
-func (layout *FlatEvenLayout) polygon_corners(h Hex) [6]Point {
-	var corners [6]Point
-	center := layout.hex_to_pixel(h)
-	for i := 0; i < 6; i++ {
-		offset := layout.hex_corner_offset(i)
-		corners[i] = Point{X: center.X + offset.X, Y: center.Y + offset.Y}
-	}
-	return corners
-}
-
-func (layout *FlatOddLayout) polygon_corners(h Hex) [6]Point {
-	var corners [6]Point
-	center := layout.HexToCenterPoint(h)
-	for i := 0; i < 6; i++ {
-		offset := layout.hex_corner_offset(i)
-		corners[i] = Point{X: center.X + offset.X, Y: center.Y + offset.Y}
-	}
-	return corners
-}
-
-func (layout *PointyEvenLayout) polygon_corners(h Hex) [6]Point {
-	var corners [6]Point
-	center := layout.hex_to_pixel(h)
-	for i := 0; i < 6; i++ {
-		offset := layout.hex_corner_offset(i)
-		corners[i] = Point{X: center.X + offset.X, Y: center.Y + offset.Y}
+// hex_edge_corners returns the indexes of the corners at either end of the edge
+// between the hex and its neighbor in the given direction.
+// Corners go clockwise on screen while directions go counter-clockwise.
+func hex_edge_corners(orientation Orientation, direction int) (int, int) {
+	direction = (6 + (direction % 6)) % 6
+	if orientation.IsFlat() {
+		return (6 - direction) % 6, (7 - direction) % 6
 	}
-	return corners
+	return (5 - direction) % 6, (6 - direction) % 6
 }
 
-func (layout *FlatEvenLayout) Points(h Hex) (center Point, corners [6]Point) {
-	center = layout.hex_to_pixel(h)
-	for i := 0; i < 6; i++ {
-		offset := layout.hex_corner_offset(i)
-		corners[i] = Point{X: center.X + offset.X, Y: center.Y + offset.Y}
+// hex_vertex_corner returns the index of the corner shared by the hex
+// and its neighbors in the given direction and the next one counter-clockwise.
+func hex_vertex_corner(orientation Orientation, direction int) int {
+	direction = (6 + (direction % 6)) % 6
+	if orientation.IsFlat() {
+		return (6 - direction) % 6
 	}
-	return center, corners
+	return 5 - direction
 }
 
-func (layout *FlatOddLayout) Points(h Hex) (center Point, corners [6]Point) {
-	center = layout.hex_to_pixel(h)
-	for i := 0; i < 6; i++ {
-		offset := layout.hex_corner_offset(i)
-		corners[i] = Point{X: center.X + offset.X, Y: center.Y + offset.Y}
-	}
-	return center, corners
-}
-
-func (layout *PointyEvenLayout) Points(h Hex) (center Point, corners [6]Point) {
+func (layout *hexLayout) Points(h Hex) (center Point, corners [6]Point) {
 	center = layout.hex_to_pixel(h)
 	for i := 0; i < 6; i++ {
 		offset := layout.hex_corner_offset(i)
@@ -342,6 +427,25 @@ type FractionalHex struct {
 	q, r, s float64
 }
 
+func NewFractionalHex(q, r, s float64) FractionalHex {
+	return FractionalHex{q: q, r: r, s: s}
+}
+
+// Q returns the q coordinate of the fractional hex.
+func (h FractionalHex) Q() float64 {
+	return h.q
+}
+
+// R returns the r coordinate of the fractional hex.
+func (h FractionalHex) R() float64 {
+	return h.r
+}
+
+// S returns the s coordinate of the fractional hex.
+func (h FractionalHex) S() float64 {
+	return h.s
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // rounding
 
@@ -358,6 +462,11 @@ func hex_round(h FractionalHex) Hex {
 	return Hex{q: int(q), r: int(r), s: int(s)}
 }
 
+// Round returns the hex that contains the fractional hex.
+func (h FractionalHex) Round() Hex {
+	return hex_round(h)
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // line drawing
 
@@ -389,12 +498,24 @@ func fractional_hex_lerp(a, b FractionalHex, t float64) FractionalHex {
 	return FractionalHex{q: q, r: r, s: s}
 }
 
+// LineTo returns the hexes on the line from h to b, including both end points.
+func (h Hex) LineTo(b Hex) []Hex {
+	return hex_linedraw(h, b)
+}
+
 func hex_linedraw_with_nudge(a, b Hex) (results []Hex) {
+	return hex_linedraw_nudged(a, b, 1e-6)
+}
+
+// hex_linedraw_nudged nudges both hex centers by epsilon to avoid points on the edge of the line.
+// Lines that run exactly along hex edges go to one side for a positive epsilon
+// and to the other side for a negative epsilon.
+func hex_linedraw_nudged(a, b Hex, epsilon float64) (results []Hex) {
 	N := hex_distance(a, b)
 
 	// nudge both hex centers to avoid points on the edge of the line
-	afh := FractionalHex{float64(a.q) + 1e-6, float64(a.r) + 1e-6, float64(a.s) - 2e-6}
-	bfh := FractionalHex{float64(b.q) + 1e-6, float64(b.r) + 1e-6, float64(b.s) - 2e-6}
+	afh := FractionalHex{float64(a.q) + epsilon, float64(a.r) + epsilon, float64(a.s) - 2*epsilon}
+	bfh := FractionalHex{float64(b.q) + epsilon, float64(b.r) + epsilon, float64(b.s) - 2*epsilon}
 
 	step := 1.0 / float64(max(N, 1))
 	for i := 0; i <= N; i++ {
@@ -415,6 +536,16 @@ func hex_rotate_right(a Hex) Hex {
 	return Hex{q: -a.r, r: -a.s, s: -a.q}
 }
 
+// RotateLeft rotates the hex 60 degrees counter-clockwise around the origin.
+func (h Hex) RotateLeft() Hex {
+	return hex_rotate_left(h)
+}
+
+// RotateRight rotates the hex 60 degrees clockwise around the origin.
+func (h Hex) RotateRight() Hex {
+	return hex_rotate_right(h)
+}
+
 // --------------------------------------------------------------------------------------------------------------------
 // offset coordinates
 //
@@ -484,26 +615,26 @@ func oddr_to_cube(column, row int) Hex {
 	return Hex{q: q, r: r, s: s}
 }
 
-func (layout *FlatEvenLayout) HexToOffset(h Hex) (column, row int) {
-	return cube_to_evenq(h)
-}
-
-func (layout *FlatEvenLayout) OffsetToHex(column, row int) Hex {
-	return evenq_to_cube(column, row)
-}
-
-func (layout *FlatOddLayout) HexToOffset(h Hex) (column, row int) {
-	return cube_to_oddq(h)
-}
-
-func (layout *FlatOddLayout) OffsetToHex(column, row int) Hex {
-	return oddq_to_cube(column, row)
-}
-
-func (layout *PointyEvenLayout) HexToOffset(h Hex) (column, row int) {
-	return cube_to_evenr(h)
+func (layout *hexLayout) HexToOffset(h Hex) (column, row int) {
+	switch {
+	case layout.orientation.IsFlat() && layout.parity == Even:
+		return cube_to_evenq(h)
+	case layout.orientation.IsFlat():
+		return cube_to_oddq(h)
+	case layout.parity == Even:
+		return cube_to_evenr(h)
+	}
+	return cube_to_oddr(h)
 }
 
-func (layout *PointyEvenLayout) OffsetToHex(column, row int) Hex {
-	return evenr_to_cube(column, row)
+func (layout *hexLayout) OffsetToHex(column, row int) Hex {
+	switch {
+	case layout.orientation.IsFlat() && layout.parity == Even:
+		return evenq_to_cube(column, row)
+	case layout.orientation.IsFlat():
+		return oddq_to_cube(column, row)
+	case layout.parity == Even:
+		return evenr_to_cube(column, row)
+	}
+	return oddr_to_cube(column, row)
 }
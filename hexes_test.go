@@ -0,0 +1,205 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"testing"
+)
+
+// The expected values come from the tests in Red Blob Games' lib.cpp.
+
+func TestHexAdd(t *testing.T) {
+	for _, tc := range []struct {
+		id         int
+		a, b, want Hex
+	}{
+		{1, NewHex(1, -3, 2), NewHex(3, -7, 4), NewHex(4, -10, 6)},
+		{2, NewHex(0, 0, 0), NewHex(1, -1, 0), NewHex(1, -1, 0)},
+		{3, NewHex(-2, 1, 1), NewHex(2, -1, -1), NewHex(0, 0, 0)},
+	} {
+		if got := tc.a.Add(tc.b); got != tc.want {
+			t.Errorf("%d: %v.Add(%v): want %v: got %v", tc.id, tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestHexSub(t *testing.T) {
+	for _, tc := range []struct {
+		id         int
+		a, b, want Hex
+	}{
+		{1, NewHex(1, -3, 2), NewHex(3, -7, 4), NewHex(-2, 4, -2)},
+		{2, NewHex(1, -1, 0), NewHex(1, -1, 0), NewHex(0, 0, 0)},
+		{3, NewHex(0, 0, 0), NewHex(2, -1, -1), NewHex(-2, 1, 1)},
+	} {
+		if got := tc.a.Sub(tc.b); got != tc.want {
+			t.Errorf("%d: %v.Sub(%v): want %v: got %v", tc.id, tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestHexScale(t *testing.T) {
+	for _, tc := range []struct {
+		id   int
+		h    Hex
+		k    int
+		want Hex
+	}{
+		{1, NewHex(1, -3, 2), 2, NewHex(2, -6, 4)},
+		{2, NewHex(1, -3, 2), 0, NewHex(0, 0, 0)},
+		{3, NewHex(1, -3, 2), -1, NewHex(-1, 3, -2)},
+	} {
+		if got := tc.h.Scale(tc.k); got != tc.want {
+			t.Errorf("%d: %v.Scale(%d): want %v: got %v", tc.id, tc.h, tc.k, tc.want, got)
+		}
+	}
+}
+
+func TestHexLength(t *testing.T) {
+	for _, tc := range []struct {
+		id   int
+		h    Hex
+		want int
+	}{
+		{1, NewHex(3, -7, 4), 7},
+		{2, NewHex(0, 0, 0), 0},
+		{3, NewHex(-1, 1, 0), 1},
+	} {
+		if got := tc.h.Length(); got != tc.want {
+			t.Errorf("%d: %v.Length(): want %d: got %d", tc.id, tc.h, tc.want, got)
+		}
+	}
+}
+
+func TestHexDistanceTo(t *testing.T) {
+	for _, tc := range []struct {
+		id   int
+		a, b Hex
+		want int
+	}{
+		{1, NewHex(3, -7, 4), NewHex(0, 0, 0), 7},
+		{2, NewHex(0, 0, 0), NewHex(3, -7, 4), 7},
+		{3, NewHex(1, -3, 2), NewHex(3, -7, 4), 4},
+		{4, NewHex(1, -3, 2), NewHex(1, -3, 2), 0},
+	} {
+		if got := tc.a.DistanceTo(tc.b); got != tc.want {
+			t.Errorf("%d: %v.DistanceTo(%v): want %d: got %d", tc.id, tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestHexNeighbor(t *testing.T) {
+	for _, tc := range []struct {
+		id        int
+		h         Hex
+		direction Direction
+		want      Hex
+	}{
+		{1, NewHex(1, -2, 1), 2, NewHex(1, -3, 2)},
+		{2, NewHex(0, 0, 0), 0, NewHex(1, 0, -1)},
+		{3, NewHex(0, 0, 0), 5, NewHex(0, 1, -1)},
+		{4, NewHex(0, 0, 0), 6, NewHex(1, 0, -1)},
+		{5, NewHex(0, 0, 0), -1, NewHex(0, 1, -1)},
+	} {
+		if got := tc.h.Neighbor(tc.direction); got != tc.want {
+			t.Errorf("%d: %v.Neighbor(%d): want %v: got %v", tc.id, tc.h, tc.direction, tc.want, got)
+		}
+	}
+}
+
+func TestHexDiagonalNeighbor(t *testing.T) {
+	for _, tc := range []struct {
+		id        int
+		h         Hex
+		direction Direction
+		want      Hex
+	}{
+		{1, NewHex(1, -2, 1), 3, NewHex(-1, -1, 2)},
+		{2, NewHex(0, 0, 0), 0, NewHex(2, -1, -1)},
+		{3, NewHex(0, 0, 0), 5, NewHex(1, 1, -2)},
+		{4, NewHex(0, 0, 0), -3, NewHex(-2, 1, 1)},
+	} {
+		if got := tc.h.DiagonalNeighbor(tc.direction); got != tc.want {
+			t.Errorf("%d: %v.DiagonalNeighbor(%d): want %v: got %v", tc.id, tc.h, tc.direction, tc.want, got)
+		}
+	}
+}
+
+func TestHexRotateLeft(t *testing.T) {
+	for _, tc := range []struct {
+		id      int
+		h, want Hex
+	}{
+		{1, NewHex(1, -3, 2), NewHex(-2, -1, 3)},
+		{2, NewHex(1, 0, -1), NewHex(1, -1, 0)},
+		{3, NewHex(0, 0, 0), NewHex(0, 0, 0)},
+	} {
+		if got := tc.h.RotateLeft(); got != tc.want {
+			t.Errorf("%d: %v.RotateLeft(): want %v: got %v", tc.id, tc.h, tc.want, got)
+		}
+	}
+}
+
+func TestHexRotateRight(t *testing.T) {
+	for _, tc := range []struct {
+		id      int
+		h, want Hex
+	}{
+		{1, NewHex(1, -3, 2), NewHex(3, -2, -1)},
+		{2, NewHex(1, -1, 0), NewHex(1, 0, -1)},
+		{3, NewHex(0, 0, 0), NewHex(0, 0, 0)},
+	} {
+		if got := tc.h.RotateRight(); got != tc.want {
+			t.Errorf("%d: %v.RotateRight(): want %v: got %v", tc.id, tc.h, tc.want, got)
+		}
+	}
+}
+
+func TestHexEqual(t *testing.T) {
+	for _, tc := range []struct {
+		id   int
+		a, b Hex
+		want bool
+	}{
+		{1, NewHex(1, -3, 2), NewHex(1, -3, 2), true},
+		{2, NewHex(1, -3, 2), NewHex(-3, 1, 2), false},
+		{3, NewHex(0, 0, 0), Hex{}, true},
+	} {
+		if got := tc.a.Equal(tc.b); got != tc.want {
+			t.Errorf("%d: %v.Equal(%v): want %v: got %v", tc.id, tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestHexRound(t *testing.T) {
+	a, b, c := NewFractionalHex(0, 0, 0), NewFractionalHex(1, -1, 0), NewFractionalHex(0, -1, 1)
+	for _, tc := range []struct {
+		id   int
+		h    FractionalHex
+		want Hex
+	}{
+		{1, fractional_hex_lerp(NewFractionalHex(0, 0, 0), NewFractionalHex(10, -20, 10), 0.5), NewHex(5, -10, 5)},
+		{2, a, NewHex(0, 0, 0)},
+		{3, fractional_hex_lerp(a, b, 0.499), NewHex(0, 0, 0)},
+		{4, fractional_hex_lerp(a, b, 0.501), NewHex(1, -1, 0)},
+		{5, NewFractionalHex(a.q*0.4+b.q*0.3+c.q*0.3, a.r*0.4+b.r*0.3+c.r*0.3, a.s*0.4+b.s*0.3+c.s*0.3), NewHex(0, 0, 0)},
+		{6, NewFractionalHex(a.q*0.3+b.q*0.3+c.q*0.4, a.r*0.3+b.r*0.3+c.r*0.4, a.s*0.3+b.s*0.3+c.s*0.4), NewHex(0, -1, 1)},
+	} {
+		if got := tc.h.Round(); got != tc.want {
+			t.Errorf("%d: %v.Round(): want %v: got %v", tc.id, tc.h, tc.want, got)
+		}
+	}
+}
+
+func TestHexLineTo(t *testing.T) {
+	want := []Hex{NewHex(0, 0, 0), NewHex(0, -1, 1), NewHex(0, -2, 2), NewHex(1, -3, 2), NewHex(1, -4, 3), NewHex(1, -5, 4)}
+	got := NewHex(0, 0, 0).LineTo(NewHex(1, -5, 4))
+	if len(got) != len(want) {
+		t.Fatalf("LineTo: want %v: got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("LineTo: %d: want %v: got %v", i, want[i], got[i])
+		}
+	}
+}
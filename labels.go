@@ -0,0 +1,182 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// labels
+//
+// Wargame maps label hexes with the column and row, "0304" is column 3, row 4.
+// Large maps are printed on several sheets and the label starts with two letters
+// that select the sheet, "AB 0304". The first letter is the row of sheets and the
+// second is the column of sheets, so "AB" is the sheet to the right of "AA".
+//
+// Labels are converted through the offset coordinates of the layout, using the
+// column and row across all the sheets. Moving off the edge of one sheet lands
+// on the next one without any special handling.
+
+var (
+	// ErrOffMap is returned when a hex is outside the area that can be labeled.
+	ErrOffMap = errors.New("hex is off the map")
+)
+
+// Labeler formats and parses wargame style hex labels.
+type Labeler struct {
+	Layout Layout
+
+	// SheetColumns and SheetRows are the number of columns and rows on each sheet.
+	// If either is zero, the map is a single sheet and labels have no sheet prefix.
+	SheetColumns, SheetRows int
+
+	// ZeroBased numbers columns and rows from 0 instead of 1.
+	ZeroBased bool
+
+	// Digits is the number of digits in the column and in the row. Zero means 2.
+	Digits int
+
+	// Letters are used for the sheet prefix. Empty means "A" through "Z".
+	Letters string
+}
+
+func (l Labeler) has_sheets() bool {
+	return l.SheetColumns > 0 && l.SheetRows > 0
+}
+
+func (l Labeler) digits() int {
+	if l.Digits == 0 {
+		return 2
+	}
+	return l.Digits
+}
+
+func (l Labeler) letters() []rune {
+	if l.Letters == "" {
+		return []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	}
+	return []rune(l.Letters)
+}
+
+func (l Labeler) base() int {
+	if l.ZeroBased {
+		return 0
+	}
+	return 1
+}
+
+// Format returns the label for the hex.
+// It returns ErrOffMap if the hex can't be labeled.
+func (l Labeler) Format(h Hex) (string, error) {
+	fail := func(err error) (string, error) {
+		return "", fmt.Errorf("label %v: %w", h, err)
+	}
+
+	column, row := l.Layout.HexToOffset(h)
+	if column < 0 || row < 0 {
+		return fail(ErrOffMap)
+	}
+
+	prefix := ""
+	if l.has_sheets() {
+		letters := l.letters()
+		sheetRow, sheetColumn := row/l.SheetRows, column/l.SheetColumns
+		if sheetRow >= len(letters) || sheetColumn >= len(letters) {
+			return fail(ErrOffMap)
+		}
+		prefix = string(letters[sheetRow]) + string(letters[sheetColumn]) + " "
+		column, row = column%l.SheetColumns, row%l.SheetRows
+	}
+
+	digits := l.digits()
+	column, row = column+l.base(), row+l.base()
+	if len(strconv.Itoa(column)) > digits || len(strconv.Itoa(row)) > digits {
+		return fail(ErrOffMap)
+	}
+
+	return fmt.Sprintf("%s%0*d%0*d", prefix, digits, column, digits, row), nil
+}
+
+// Parse returns the hex for the label.
+func (l Labeler) Parse(s string) (Hex, error) {
+	input := s
+	fail := func(err error) (Hex, error) {
+		return Hex{}, &ParseError{Func: "Labeler.Parse", Input: input, Err: err}
+	}
+
+	s = strings.TrimSpace(s)
+	var sheetRow, sheetColumn int
+	if l.has_sheets() {
+		letters := l.letters()
+		runes := []rune(s)
+		if len(runes) < 2 {
+			return fail(ErrSyntax)
+		}
+		sheetRow, sheetColumn = index_of_rune(letters, runes[0]), index_of_rune(letters, runes[1])
+		if sheetRow < 0 || sheetColumn < 0 {
+			return fail(ErrSyntax)
+		}
+		s = strings.TrimSpace(string(runes[2:]))
+	}
+
+	digits := l.digits()
+	if len(s) != 2*digits || strings.IndexFunc(s, func(r rune) bool { return r < '0' || '9' < r }) != -1 {
+		return fail(ErrSyntax)
+	}
+	column, err := parse_label_number(s[:digits])
+	if err != nil {
+		return fail(err)
+	}
+	row, err := parse_label_number(s[digits:])
+	if err != nil {
+		return fail(err)
+	}
+	column, row = column-l.base(), row-l.base()
+	if column < 0 || row < 0 {
+		return fail(ErrOffMap)
+	}
+
+	if l.has_sheets() {
+		if column >= l.SheetColumns || row >= l.SheetRows {
+			return fail(ErrOffMap)
+		}
+		column, row = sheetColumn*l.SheetColumns+column, sheetRow*l.SheetRows+row
+	}
+
+	return l.Layout.OffsetToHex(column, row), nil
+}
+
+// Neighbor returns the label of the neighbor of the labeled hex in the given direction.
+func (l Labeler) Neighbor(label string, direction Direction) (string, error) {
+	h, err := l.Parse(label)
+	if err != nil {
+		return "", err
+	}
+	return l.Format(hex_neighbor(h, int(direction)))
+}
+
+// parse_label_number returns the column or row in the label.
+func parse_label_number(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, strconv.ErrRange
+		}
+		return 0, ErrSyntax
+	}
+	return n, nil
+}
+
+func index_of_rune(runes []rune, r rune) int {
+	for i := range runes {
+		if runes[i] == unicode.ToUpper(r) || runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestLabelerRoundTrip(t *testing.T) {
+	layout := NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0))
+	for _, tc := range []struct {
+		id          int
+		labeler     Labeler
+		column, row int
+		want        string
+	}{
+		{1, Labeler{Layout: layout}, 2, 3, "0304"},
+		{2, Labeler{Layout: layout, ZeroBased: true}, 2, 3, "0203"},
+		{3, Labeler{Layout: layout, Digits: 3}, 11, 0, "012001"},
+		{4, Labeler{Layout: layout, SheetColumns: 10, SheetRows: 8}, 12, 3, "AB 0304"},
+		{5, Labeler{Layout: layout, SheetColumns: 10, SheetRows: 8}, 2, 17, "CA 0302"},
+	} {
+		h := layout.OffsetToHex(tc.column, tc.row)
+		label, err := tc.labeler.Format(h)
+		if err != nil || label != tc.want {
+			t.Errorf("%d: Format(%v): want %q: got %q, %v", tc.id, h, tc.want, label, err)
+			continue
+		}
+		if got, err := tc.labeler.Parse(label); err != nil || got != h {
+			t.Errorf("%d: Parse(%q): want %v: got %v, %v", tc.id, label, h, got, err)
+		}
+	}
+}
+
+func TestLabelerParseErrors(t *testing.T) {
+	layout := NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0))
+	for _, tc := range []struct {
+		id      int
+		labeler Labeler
+		input   string
+		want    error
+	}{
+		{1, Labeler{Layout: layout}, "03x4", ErrSyntax},
+		{2, Labeler{Layout: layout}, "030", ErrSyntax},
+		{3, Labeler{Layout: layout, Digits: 4}, "٠٣٠٤", ErrSyntax}, // Arabic-Indic digits are two bytes each
+		{4, Labeler{Layout: layout}, "0300", ErrOffMap},
+		{5, Labeler{Layout: layout, Digits: 20}, "9999999999999999999900000000000000000001", strconv.ErrRange},
+		{6, Labeler{Layout: layout, SheetColumns: 10, SheetRows: 8}, "0304", ErrSyntax},
+		{7, Labeler{Layout: layout, SheetColumns: 10, SheetRows: 8}, "AA 1104", ErrOffMap},
+	} {
+		_, err := tc.labeler.Parse(tc.input)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, tc.want) {
+			t.Errorf("%d: Parse(%q): want *ParseError wrapping %v: got %v", tc.id, tc.input, tc.want, err)
+		}
+	}
+}
+
+func TestLabelerFormatErrors(t *testing.T) {
+	layout := NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0))
+	for _, tc := range []struct {
+		id          int
+		labeler     Labeler
+		column, row int
+	}{
+		{1, Labeler{Layout: layout}, -1, 0},
+		{2, Labeler{Layout: layout}, 0, -1},
+		{3, Labeler{Layout: layout}, 99, 0},
+		{4, Labeler{Layout: layout, SheetColumns: 10, SheetRows: 8, Letters: "AB"}, 20, 0},
+	} {
+		_, err := tc.labeler.Format(layout.OffsetToHex(tc.column, tc.row))
+		var pe *ParseError
+		if errors.As(err, &pe) || !errors.Is(err, ErrOffMap) {
+			t.Errorf("%d: Format(%d, %d): want %v: got %v", tc.id, tc.column, tc.row, ErrOffMap, err)
+		}
+	}
+}
+
+// TestLabelerNeighbor steps across the edges of 30 x 21 sheets.
+func TestLabelerNeighbor(t *testing.T) {
+	size, origin := NewPoint(1, 1), NewPoint(0, 0)
+	flatEven := Labeler{Layout: NewFlatEvenLayout(size, origin), SheetColumns: 30, SheetRows: 21}
+	flatOdd := Labeler{Layout: NewFlatOddLayout(size, origin), SheetColumns: 30, SheetRows: 21}
+	pointyEven := Labeler{Layout: NewPointyEvenLayout(size, origin), SheetColumns: 30, SheetRows: 21}
+	pointyOdd := Labeler{Layout: NewPointyOddLayout(size, origin), SheetColumns: 30, SheetRows: 21}
+	twoSheets := flatOdd
+	twoSheets.Letters = "AB"
+
+	const flatSE, flatNE, flatN, flatNW, flatS = 0, 1, 2, 3, 5
+	const pointyE, pointySE = 0, 5
+	for _, tc := range []struct {
+		id        int
+		labeler   Labeler
+		label     string
+		direction Direction
+		want      string
+		err       error
+	}{
+		{1, flatOdd, "AA 3021", flatSE, "BB 0101", nil},  // both edges
+		{2, flatOdd, "AA 3010", flatNE, "AB 0110", nil},  // column edge
+		{3, flatOdd, "AA 1521", flatS, "BA 1501", nil},   // row edge
+		{4, flatOdd, "BA 0101", flatN, "AA 0121", nil},   // row edge, going back
+		{5, flatOdd, "AB 0105", flatNW, "AA 3004", nil},  // column edge, going back
+		{6, flatEven, "AA 3021", flatSE, "AB 0121", nil}, // column edge
+		{7, flatEven, "AA 2921", flatSE, "BA 3001", nil}, // row edge
+		{8, flatEven, "BA 3001", flatNE, "AB 0121", nil}, // both edges, forward and back
+		{9, pointyEven, "AA 3021", pointySE, "BB 0101", nil},
+		{10, pointyOdd, "AA 3021", pointySE, "BA 3001", nil},
+		{11, pointyOdd, "AA 3021", pointyE, "AB 0121", nil},
+		{12, flatOdd, "AB 0101", flatNW, "", ErrOffMap}, // off the top
+		{13, flatOdd, "AA 0101", flatN, "", ErrOffMap},
+		{14, twoSheets, "BB 3021", flatSE, "", ErrOffMap}, // off the last sheet
+		{15, twoSheets, "AB 3010", flatNE, "", ErrOffMap},
+		{16, flatOdd, "AA 3121", flatSE, "", ErrOffMap}, // not a hex on the sheet
+	} {
+		got, err := tc.labeler.Neighbor(tc.label, tc.direction)
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("%d: Neighbor(%q, %d): want %q, %v: got %q, %v", tc.id, tc.label, tc.direction, tc.want, tc.err, got, err)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"testing"
+)
+
+// test_layouts returns every layout with a size that isn't square and an origin that isn't zero.
+func test_layouts() map[string]Layout {
+	size, origin := NewPoint(10, 15), NewPoint(35, 71)
+	return map[string]Layout{
+		"flat-even":   NewFlatEvenLayout(size, origin),
+		"flat-odd":    NewFlatOddLayout(size, origin),
+		"pointy-even": NewPointyEvenLayout(size, origin),
+		"pointy-odd":  NewPointyOddLayout(size, origin),
+	}
+}
+
+func TestLayoutRoundTrip(t *testing.T) {
+	for name, layout := range test_layouts() {
+		for _, h := range NewHexagonGrid[bool](8).Hexes() {
+			p := layout.HexToCenterPoint(h)
+			if got := layout.PixelToHex(p); got != h {
+				t.Errorf("%s: PixelToHex(HexToCenterPoint(%v)): got %v", name, h, got)
+			}
+			if got := layout.PixelToFractionalHex(p).Round(); got != h {
+				t.Errorf("%s: PixelToFractionalHex(HexToCenterPoint(%v)).Round(): got %v", name, h, got)
+			}
+		}
+	}
+}
+
+func TestLayoutRedBlob(t *testing.T) {
+	h := NewHex(3, 4, -7)
+	for name, layout := range test_layouts() {
+		if got := layout.PixelToHex(layout.HexToCenterPoint(h)); got != h {
+			t.Errorf("%s: want %v: got %v", name, h, got)
+		}
+	}
+}
+
+// TestLayoutCorners checks points just inside each corner of a hex.
+func TestLayoutCorners(t *testing.T) {
+	for name, layout := range test_layouts() {
+		for _, h := range NewHexagonGrid[bool](4).Hexes() {
+			center, corners := layout.Points(h)
+			for i, corner := range corners {
+				if got := layout.PixelToHex(corner.Lerp(center, 0.01)); got != h {
+					t.Errorf("%s: %v: corner %d: got %v", name, h, i, got)
+				}
+			}
+		}
+	}
+}
+
+// TestLayoutEdges checks points just inside and just outside the middle of each edge of a hex.
+// The middle of an edge is halfway between the centers of the hexes on either side.
+func TestLayoutEdges(t *testing.T) {
+	for name, layout := range test_layouts() {
+		for _, h := range NewHexagonGrid[bool](4).Hexes() {
+			center, corners := layout.Points(h)
+			for i := range corners {
+				middle := corners[i].Lerp(corners[(i+1)%6], 0.5)
+				if got := layout.PixelToHex(middle.Lerp(center, 0.01)); got != h {
+					t.Errorf("%s: %v: edge %d: inside: got %v", name, h, i, got)
+				}
+
+				var neighbor Hex
+				found := false
+				for direction := Direction(0); direction < 6; direction++ {
+					n := h.Neighbor(direction)
+					if layout.HexToCenterPoint(n).Distance(middle.Scale(2).Sub(center)) < 1e-9 {
+						neighbor, found = n, true
+					}
+				}
+				if !found {
+					t.Fatalf("%s: %v: edge %d: no neighbor across the edge", name, h, i)
+				}
+				if got := layout.PixelToHex(middle.Lerp(center, -0.01)); got != neighbor {
+					t.Errorf("%s: %v: edge %d: outside: want %v: got %v", name, h, i, neighbor, got)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutOffsets(t *testing.T) {
+	for _, tc := range []struct {
+		id          int
+		layout      Layout
+		h           Hex
+		column, row int
+	}{
+		// from Red Blob Games' lib.cpp
+		{1, NewFlatEvenLayout(NewPoint(1, 1), NewPoint(0, 0)), NewHex(1, 2, -3), 1, 3},
+		{2, NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0)), NewHex(1, 2, -3), 1, 2},
+	} {
+		if column, row := tc.layout.HexToOffset(tc.h); column != tc.column || row != tc.row {
+			t.Errorf("%d: HexToOffset(%v): want %d, %d: got %d, %d", tc.id, tc.h, tc.column, tc.row, column, row)
+		}
+		if got := tc.layout.OffsetToHex(tc.column, tc.row); got != tc.h {
+			t.Errorf("%d: OffsetToHex(%d, %d): want %v: got %v", tc.id, tc.column, tc.row, tc.h, got)
+		}
+	}
+	for name, layout := range test_layouts() {
+		for _, h := range NewHexagonGrid[bool](8).Hexes() {
+			if got := layout.OffsetToHex(layout.HexToOffset(h)); got != h {
+				t.Errorf("%s: OffsetToHex(HexToOffset(%v)): got %v", name, h, got)
+			}
+		}
+	}
+}
+
+func TestLayoutTypes(t *testing.T) {
+	size, origin := NewPoint(10, 15), NewPoint(35, 71)
+	if _, ok := NewFlatEvenLayout(size, origin).(*FlatEvenLayout); !ok {
+		t.Errorf("NewFlatEvenLayout: want *FlatEvenLayout")
+	}
+	if _, ok := NewFlatOddLayout(size, origin).(*FlatOddLayout); !ok {
+		t.Errorf("NewFlatOddLayout: want *FlatOddLayout")
+	}
+	if _, ok := NewPointyEvenLayout(size, origin).(*PointyEvenLayout); !ok {
+		t.Errorf("NewPointyEvenLayout: want *PointyEvenLayout")
+	}
+	if _, ok := NewPointyOddLayout(size, origin).(*PointyOddLayout); !ok {
+		t.Errorf("NewPointyOddLayout: want *PointyOddLayout")
+	}
+	if _, ok := NewLayout(Pointy(), Even, size, origin).(*PointyEvenLayout); !ok {
+		t.Errorf("NewLayout(Pointy(), Even): want *PointyEvenLayout")
+	}
+	if !Flat().IsFlat() || !Pointy().IsPointy() {
+		t.Errorf("Flat and Pointy: want flat and pointy orientations")
+	}
+}
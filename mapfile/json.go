@@ -0,0 +1,199 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package mapfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+)
+
+// The JSON format is a single object. The header fields come first and
+// the hexes are the last field, so that they can be streamed:
+//
+//	{
+//	  "version": 1,
+//	  "layout": "flat-even",
+//	  "size": {"x": 50, "y": 50},
+//	  "origin": {"x": 0, "y": 0},
+//	  "shape": {"kind": "hexagon", "radius": 5},
+//	  "hexes": [
+//	    {"hex": "(0, 0, 0)", "terrain": "forest", "attributes": {"owner": "red"}}
+//	  ]
+//	}
+
+type json_point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type json_hex struct {
+	Hex        hexes.Hex         `json:"hex"`
+	Terrain    string            `json:"terrain,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// writer
+
+type jsonWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+// NewJSONWriter writes the header and returns a writer for the hexes.
+func NewJSONWriter(w io.Writer, header Header) (Writer, error) {
+	header.Version = Version
+	if err := header.validate(); err != nil {
+		return nil, err
+	}
+	jw := &jsonWriter{w: bufio.NewWriter(w)}
+	fields := []struct {
+		key   string
+		value any
+	}{
+		{"version", header.Version},
+		{"layout", header.Layout},
+		{"size", json_point{X: header.Size.X, Y: header.Size.Y}},
+		{"origin", json_point{X: header.Origin.X, Y: header.Origin.Y}},
+		{"shape", header.Shape},
+	}
+	_, _ = jw.w.WriteString("{\n")
+	for _, field := range fields {
+		buf, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = fmt.Fprintf(jw.w, "  %q: %s,\n", field.key, buf)
+	}
+	_, _ = jw.w.WriteString("  \"hexes\": [")
+	return jw, nil
+}
+
+func (jw *jsonWriter) WriteHex(h Hex) error {
+	buf, err := json.Marshal(json_hex{Hex: h.Hex, Terrain: h.Terrain, Attributes: h.Attributes})
+	if err != nil {
+		return err
+	}
+	if jw.count != 0 {
+		_, _ = jw.w.WriteString(",")
+	}
+	jw.count++
+	_, _ = jw.w.WriteString("\n    ")
+	_, err = jw.w.Write(buf)
+	return err
+}
+
+func (jw *jsonWriter) Close() error {
+	if jw.count != 0 {
+		_, _ = jw.w.WriteString("\n  ")
+	}
+	_, _ = jw.w.WriteString("]\n}\n")
+	return jw.w.Flush()
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// reader
+
+type jsonReader struct {
+	dec    *json.Decoder
+	header Header
+	done   bool
+}
+
+// NewJSONReader reads the header and returns a reader for the hexes.
+func NewJSONReader(r io.Reader) (Reader, error) {
+	jr := &jsonReader{dec: json.NewDecoder(r)}
+	if err := jr.expect(json.Delim('{')); err != nil {
+		return nil, err
+	}
+	for {
+		if !jr.dec.More() {
+			// the object has no hexes
+			if err := jr.expect(json.Delim('}')); err != nil {
+				return nil, err
+			}
+			jr.done = true
+			break
+		}
+		tok, err := jr.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		if key == "hexes" {
+			if err := jr.expect(json.Delim('[')); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if err := jr.decode_header_field(key); err != nil {
+			return nil, err
+		}
+	}
+	if err := jr.header.validate(); err != nil {
+		return nil, err
+	}
+	return jr, nil
+}
+
+func (jr *jsonReader) decode_header_field(key string) error {
+	var pt json_point
+	switch key {
+	case "version":
+		return jr.dec.Decode(&jr.header.Version)
+	case "layout":
+		return jr.dec.Decode(&jr.header.Layout)
+	case "size":
+		if err := jr.dec.Decode(&pt); err != nil {
+			return err
+		}
+		jr.header.Size = hexes.NewPoint(pt.X, pt.Y)
+	case "origin":
+		if err := jr.dec.Decode(&pt); err != nil {
+			return err
+		}
+		jr.header.Origin = hexes.NewPoint(pt.X, pt.Y)
+	case "shape":
+		return jr.dec.Decode(&jr.header.Shape)
+	default:
+		// ignore fields that we don't know about
+		var skip json.RawMessage
+		return jr.dec.Decode(&skip)
+	}
+	return nil
+}
+
+func (jr *jsonReader) expect(delim json.Delim) error {
+	tok, err := jr.dec.Token()
+	if err != nil {
+		return err
+	} else if tok != delim {
+		return fmt.Errorf("expected %q: %w", delim, ErrFormat)
+	}
+	return nil
+}
+
+func (jr *jsonReader) Header() Header {
+	return jr.header
+}
+
+func (jr *jsonReader) Next() (Hex, error) {
+	if jr.done {
+		return Hex{}, io.EOF
+	}
+	if !jr.dec.More() {
+		jr.done = true
+		if err := jr.expect(json.Delim(']')); err != nil {
+			return Hex{}, err
+		}
+		return Hex{}, io.EOF
+	}
+	var h json_hex
+	if err := jr.dec.Decode(&h); err != nil {
+		return Hex{}, err
+	}
+	return Hex{Hex: h.Hex, Terrain: h.Terrain, Attributes: h.Attributes}, nil
+}
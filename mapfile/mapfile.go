@@ -0,0 +1,187 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package mapfile reads and writes hex maps.
+//
+// A map file has a header that records the layout, hex size and origin,
+// and the shape of the grid, followed by one record for each hex with its
+// terrain and attributes. There are two formats with the same content:
+// JSON, and a compact line-oriented text format.
+//
+// Both formats are streamed. Writers emit each hex as it is written and
+// readers return each hex as it is read, so maps do not have to fit in memory.
+package mapfile
+
+import (
+	"errors"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+)
+
+// Version is the version of the file format written by this package.
+// Readers accept files with this version or older.
+const Version = 1
+
+var (
+	ErrVersion = errors.New("unsupported version")
+	ErrLayout  = errors.New("unknown layout")
+	ErrShape   = errors.New("unknown shape")
+	ErrFormat  = errors.New("invalid format")
+)
+
+// LayoutType names the orientation and offset parity of a layout.
+type LayoutType string
+
+const (
+	FlatEven   LayoutType = "flat-even"
+	FlatOdd    LayoutType = "flat-odd"
+	PointyEven LayoutType = "pointy-even"
+	PointyOdd  LayoutType = "pointy-odd"
+)
+
+// LayoutTypeOf returns the type of the layout.
+func LayoutTypeOf(layout hexes.Layout) LayoutType {
+	switch {
+	case layout.Orientation().IsFlat() && layout.Parity() == hexes.Even:
+		return FlatEven
+	case layout.Orientation().IsFlat():
+		return FlatOdd
+	case layout.Parity() == hexes.Even:
+		return PointyEven
+	}
+	return PointyOdd
+}
+
+// NewLayout returns a layout of this type.
+func (t LayoutType) NewLayout(size, origin hexes.Point) (hexes.Layout, error) {
+	switch t {
+	case FlatEven:
+		return hexes.NewFlatEvenLayout(size, origin), nil
+	case FlatOdd:
+		return hexes.NewFlatOddLayout(size, origin), nil
+	case PointyEven:
+		return hexes.NewPointyEvenLayout(size, origin), nil
+	case PointyOdd:
+		return hexes.NewPointyOddLayout(size, origin), nil
+	}
+	return nil, fmt.Errorf("%q: %w", string(t), ErrLayout)
+}
+
+// Shape records how the grid was built. An empty Kind means the grid
+// is just the hexes in the file.
+type Shape struct {
+	Kind    string `json:"kind"` // "hexagon", "rectangle", "parallelogram", "triangle" or ""
+	Radius  int    `json:"radius,omitempty"`
+	Columns int    `json:"columns,omitempty"`
+	Rows    int    `json:"rows,omitempty"`
+	Q1      int    `json:"q1,omitempty"`
+	Q2      int    `json:"q2,omitempty"`
+	R1      int    `json:"r1,omitempty"`
+	R2      int    `json:"r2,omitempty"`
+	Size    int    `json:"size,omitempty"`
+}
+
+// Hexes returns the hexes in the shape. Rectangles need the layout for its offset coordinates.
+func (s Shape) Hexes(layout hexes.Layout) ([]hexes.Hex, error) {
+	switch s.Kind {
+	case "":
+		return nil, nil
+	case "hexagon":
+		return hexes.NewHexagonGrid[bool](s.Radius).Hexes(), nil
+	case "rectangle":
+		return hexes.NewRectangleGrid[bool](layout, s.Columns, s.Rows).Hexes(), nil
+	case "parallelogram":
+		return hexes.NewParallelogramGrid[bool](s.Q1, s.Q2, s.R1, s.R2).Hexes(), nil
+	case "triangle":
+		return hexes.NewTriangleGrid[bool](s.Size).Hexes(), nil
+	}
+	return nil, fmt.Errorf("%q: %w", s.Kind, ErrShape)
+}
+
+// Header is the information stored at the start of a map file.
+type Header struct {
+	Version int // set by the writers
+	Layout  LayoutType
+	Size    hexes.Point
+	Origin  hexes.Point
+	Shape   Shape
+}
+
+// NewLayout returns the layout described by the header.
+func (h Header) NewLayout() (hexes.Layout, error) {
+	return h.Layout.NewLayout(h.Size, h.Origin)
+}
+
+// HeaderFor returns a header for the layout and shape.
+func HeaderFor(layout hexes.Layout, shape Shape) Header {
+	return Header{
+		Version: Version,
+		Layout:  LayoutTypeOf(layout),
+		Size:    layout.Size(),
+		Origin:  layout.Origin(),
+		Shape:   shape,
+	}
+}
+
+func (h Header) validate() error {
+	if h.Version < 1 || h.Version > Version {
+		return fmt.Errorf("%d: %w", h.Version, ErrVersion)
+	}
+	if _, err := h.NewLayout(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Hex is the data stored for a single hex.
+type Hex struct {
+	Hex        hexes.Hex
+	Terrain    string
+	Attributes map[string]string
+}
+
+// Reader returns the hexes in a map file.
+type Reader interface {
+	// Header returns the header of the file.
+	Header() Header
+	// Next returns the next hex in the file, or io.EOF at the end of the file.
+	Next() (Hex, error)
+}
+
+// Writer writes the hexes in a map file.
+type Writer interface {
+	// WriteHex writes the hex to the file.
+	WriteHex(h Hex) error
+	// Close finishes the file. It does not close the underlying writer.
+	Close() error
+}
+
+// Map is a complete map file held in memory.
+type Map struct {
+	Header Header
+	Hexes  []Hex
+}
+
+// ReadAll reads the rest of the file into memory.
+func ReadAll(r Reader) (*Map, error) {
+	m := &Map{Header: r.Header()}
+	for {
+		h, err := r.Next()
+		if err == io.EOF {
+			return m, nil
+		} else if err != nil {
+			return nil, err
+		}
+		m.Hexes = append(m.Hexes, h)
+	}
+}
+
+// WriteAll writes the hexes and closes the writer.
+func WriteAll(w Writer, hv []Hex) error {
+	for _, h := range hv {
+		if err := w.WriteHex(h); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
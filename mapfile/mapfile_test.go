@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package mapfile
+
+import (
+	"bytes"
+	"errors"
+	"github.com/playbymail/hexes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// formats are the writers and readers for each file format.
+var formats = []struct {
+	name   string
+	writer func(w io.Writer, header Header) (Writer, error)
+	reader func(r io.Reader) (Reader, error)
+}{
+	{"json", NewJSONWriter, NewJSONReader},
+	{"text", NewTextWriter, NewTextReader},
+}
+
+func TestRoundTrip(t *testing.T) {
+	headers := []Header{
+		{Layout: FlatEven, Size: hexes.NewPoint(50, 50), Origin: hexes.NewPoint(0, 0), Shape: Shape{Kind: "hexagon", Radius: 5}},
+		{Layout: FlatOdd, Size: hexes.NewPoint(12.5, 20), Origin: hexes.NewPoint(-3.25, 7)},
+		{Layout: PointyEven, Size: hexes.NewPoint(1, 1), Origin: hexes.NewPoint(0, 0), Shape: Shape{Kind: "parallelogram", Q1: -3, Q2: 0, R1: -2, R2: 4}},
+		{Layout: PointyOdd, Size: hexes.NewPoint(30, 10), Origin: hexes.NewPoint(1e6, -1e-3), Shape: Shape{Kind: "rectangle", Columns: 4, Rows: 0}},
+	}
+	hv := []Hex{
+		{Hex: hexes.NewHex(0, 0, 0), Terrain: "forest", Attributes: map[string]string{"owner": "red", "name": "Big Hill"}},
+		{Hex: hexes.NewHex(1, -1, 0)},
+		{Hex: hexes.NewHex(-2, 3, -1), Terrain: "-"},
+		{Hex: hexes.NewHex(2, 0, -2), Terrain: "deep water", Attributes: map[string]string{"a=b": "c=d", "e f": "g h", "100%": "50%", "": "empty key", "empty value": ""}},
+		{Hex: hexes.NewHex(-5, 0, 5), Terrain: "%2D", Attributes: map[string]string{"-": "-", "#": "#"}},
+	}
+
+	for _, format := range formats {
+		for i, header := range headers {
+			var buf bytes.Buffer
+			w, err := format.writer(&buf, header)
+			if err != nil {
+				t.Fatalf("%s: %d: writer: %v", format.name, i, err)
+			}
+			if err := WriteAll(w, hv); err != nil {
+				t.Fatalf("%s: %d: write: %v", format.name, i, err)
+			}
+
+			r, err := format.reader(&buf)
+			if err != nil {
+				t.Fatalf("%s: %d: reader: %v", format.name, i, err)
+			}
+			m, err := ReadAll(r)
+			if err != nil {
+				t.Fatalf("%s: %d: read: %v", format.name, i, err)
+			}
+
+			header.Version = Version
+			if !reflect.DeepEqual(m.Header, header) {
+				t.Errorf("%s: %d: header: want %+v: got %+v", format.name, i, header, m.Header)
+			}
+			if !reflect.DeepEqual(m.Hexes, hv) {
+				t.Errorf("%s: %d: hexes: want %+v: got %+v", format.name, i, hv, m.Hexes)
+			}
+		}
+	}
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	header := Header{Layout: FlatEven, Size: hexes.NewPoint(1, 1)}
+	for _, format := range formats {
+		var buf bytes.Buffer
+		w, err := format.writer(&buf, header)
+		if err != nil {
+			t.Fatalf("%s: writer: %v", format.name, err)
+		}
+		if err := WriteAll(w, nil); err != nil {
+			t.Fatalf("%s: write: %v", format.name, err)
+		}
+		r, err := format.reader(&buf)
+		if err != nil {
+			t.Fatalf("%s: reader: %v", format.name, err)
+		}
+		if m, err := ReadAll(r); err != nil {
+			t.Fatalf("%s: read: %v", format.name, err)
+		} else if len(m.Hexes) != 0 {
+			t.Errorf("%s: want no hexes: got %d", format.name, len(m.Hexes))
+		}
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	header := Header{Layout: "hex-grid", Size: hexes.NewPoint(1, 1)}
+	for _, format := range formats {
+		if _, err := format.writer(io.Discard, header); !errors.Is(err, ErrLayout) {
+			t.Errorf("%s: unknown layout: want %v: got %v", format.name, ErrLayout, err)
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format int
+		input  string
+		want   error
+	}{
+		{"json version", 0, `{"version": 2, "layout": "flat-even", "hexes": []}`, ErrVersion},
+		{"json layout", 0, `{"version": 1, "layout": "hex-grid", "hexes": []}`, ErrLayout},
+		{"text version", 1, "hexmap 2\nlayout flat-even\n", ErrVersion},
+		{"text layout", 1, "hexmap 1\nlayout hex-grid\n", ErrLayout},
+		{"text missing hexmap", 1, "layout flat-even\n", ErrFormat},
+	} {
+		if _, err := formats[tc.format].reader(strings.NewReader(tc.input)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: want %v: got %v", tc.name, tc.want, err)
+		}
+	}
+}
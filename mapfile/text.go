@@ -0,0 +1,274 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package mapfile
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The text format has one record per line. The header lines come first
+// and the "hexmap" line must be the first line in the file:
+//
+//	hexmap 1
+//	layout flat-even
+//	size 50 50
+//	origin 0 0
+//	shape hexagon radius=5
+//	hex 0,0,0 forest owner=red name=Big+Hill
+//
+// A hex line has the cube coordinates, the terrain ("-" if there is none),
+// and then any attributes. Terrain, keys and values are query escaped.
+// Blank lines and lines starting with "#" are ignored.
+
+// --------------------------------------------------------------------------------------------------------------------
+// writer
+
+type textWriter struct {
+	w *bufio.Writer
+}
+
+// NewTextWriter writes the header and returns a writer for the hexes.
+func NewTextWriter(w io.Writer, header Header) (Writer, error) {
+	header.Version = Version
+	if err := header.validate(); err != nil {
+		return nil, err
+	}
+	tw := &textWriter{w: bufio.NewWriter(w)}
+	_, _ = fmt.Fprintf(tw.w, "hexmap %d\n", header.Version)
+	_, _ = fmt.Fprintf(tw.w, "layout %s\n", header.Layout)
+	_, _ = fmt.Fprintf(tw.w, "size %s %s\n", format_float(header.Size.X), format_float(header.Size.Y))
+	_, _ = fmt.Fprintf(tw.w, "origin %s %s\n", format_float(header.Origin.X), format_float(header.Origin.Y))
+	if shape := header.Shape; shape.Kind != "" {
+		_, _ = fmt.Fprintf(tw.w, "shape %s", url.QueryEscape(shape.Kind))
+		for _, field := range shape_fields(&shape) {
+			if *field.value != 0 {
+				_, _ = fmt.Fprintf(tw.w, " %s=%d", field.key, *field.value)
+			}
+		}
+		_, _ = fmt.Fprintf(tw.w, "\n")
+	}
+	return tw, nil
+}
+
+func (tw *textWriter) WriteHex(h Hex) error {
+	terrain := url.QueryEscape(h.Terrain)
+	if terrain == "" {
+		terrain = "-"
+	} else if terrain == "-" {
+		terrain = "%2D"
+	}
+	_, _ = fmt.Fprintf(tw.w, "hex %d,%d,%d %s", h.Hex.Q(), h.Hex.R(), h.Hex.S(), terrain)
+	keys := make([]string, 0, len(h.Attributes))
+	for key := range h.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(tw.w, " %s=%s", url.QueryEscape(key), url.QueryEscape(h.Attributes[key]))
+	}
+	_, err := fmt.Fprintf(tw.w, "\n")
+	return err
+}
+
+func (tw *textWriter) Close() error {
+	return tw.w.Flush()
+}
+
+func format_float(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type shape_field struct {
+	key   string
+	value *int
+}
+
+func shape_fields(shape *Shape) []shape_field {
+	return []shape_field{
+		{"radius", &shape.Radius},
+		{"columns", &shape.Columns},
+		{"rows", &shape.Rows},
+		{"q1", &shape.Q1},
+		{"q2", &shape.Q2},
+		{"r1", &shape.R1},
+		{"r2", &shape.R2},
+		{"size", &shape.Size},
+	}
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// reader
+
+type textReader struct {
+	scanner *bufio.Scanner
+	line    int
+	header  Header
+	pending []string // the first hex line, read while looking for the end of the header
+	done    bool
+}
+
+// NewTextReader reads the header and returns a reader for the hexes.
+func NewTextReader(r io.Reader) (Reader, error) {
+	tr := &textReader{scanner: bufio.NewScanner(r)}
+	tr.scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	fields, err := tr.next_line()
+	if err == io.EOF || (err == nil && fields[0] != "hexmap") {
+		return nil, fmt.Errorf("line %d: expected hexmap: %w", tr.line, ErrFormat)
+	} else if err != nil {
+		return nil, err
+	} else if len(fields) != 2 {
+		return nil, tr.errorf("hexmap")
+	} else if tr.header.Version, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, tr.errorf("hexmap")
+	}
+
+	for {
+		fields, err = tr.next_line()
+		if err == io.EOF {
+			tr.done = true
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if fields[0] == "hex" {
+			tr.pending = fields
+			break
+		}
+		if err := tr.parse_header_line(fields); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tr.header.validate(); err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+func (tr *textReader) parse_header_line(fields []string) error {
+	switch fields[0] {
+	case "layout":
+		if len(fields) != 2 {
+			return tr.errorf("layout")
+		}
+		tr.header.Layout = LayoutType(fields[1])
+	case "size", "origin":
+		if len(fields) != 3 {
+			return tr.errorf(fields[0])
+		}
+		x, err1 := strconv.ParseFloat(fields[1], 64)
+		y, err2 := strconv.ParseFloat(fields[2], 64)
+		if err1 != nil || err2 != nil {
+			return tr.errorf(fields[0])
+		}
+		if fields[0] == "size" {
+			tr.header.Size = hexes.NewPoint(x, y)
+		} else {
+			tr.header.Origin = hexes.NewPoint(x, y)
+		}
+	case "shape":
+		if len(fields) < 2 {
+			return tr.errorf("shape")
+		}
+		kind, err := url.QueryUnescape(fields[1])
+		if err != nil {
+			return tr.errorf("shape")
+		}
+		tr.header.Shape = Shape{Kind: kind}
+		for _, kv := range fields[2:] {
+			key, value, ok := strings.Cut(kv, "=")
+			n, err := strconv.Atoi(value)
+			if !ok || err != nil {
+				return tr.errorf("shape")
+			}
+			for _, field := range shape_fields(&tr.header.Shape) {
+				if field.key == key {
+					*field.value = n
+				}
+			}
+		}
+	default:
+		// ignore records that we don't know about
+	}
+	return nil
+}
+
+func (tr *textReader) Header() Header {
+	return tr.header
+}
+
+func (tr *textReader) Next() (Hex, error) {
+	var fields []string
+	if tr.pending != nil {
+		fields, tr.pending = tr.pending, nil
+	} else if tr.done {
+		return Hex{}, io.EOF
+	} else {
+		var err error
+		if fields, err = tr.next_line(); err == io.EOF {
+			tr.done = true
+			return Hex{}, io.EOF
+		} else if err != nil {
+			return Hex{}, err
+		}
+	}
+
+	if fields[0] != "hex" || len(fields) < 3 {
+		return Hex{}, tr.errorf("hex")
+	}
+	h, err := hexes.ParseCube(fields[1])
+	if err != nil {
+		return Hex{}, fmt.Errorf("line %d: %w", tr.line, err)
+	}
+	terrain := fields[2]
+	if terrain == "-" {
+		terrain = ""
+	} else if terrain, err = url.QueryUnescape(terrain); err != nil {
+		return Hex{}, tr.errorf("hex")
+	}
+	record := Hex{Hex: h, Terrain: terrain}
+	for _, kv := range fields[3:] {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return Hex{}, tr.errorf("hex")
+		}
+		key, err1 := url.QueryUnescape(key)
+		value, err2 := url.QueryUnescape(value)
+		if err1 != nil || err2 != nil {
+			return Hex{}, tr.errorf("hex")
+		}
+		if record.Attributes == nil {
+			record.Attributes = map[string]string{}
+		}
+		record.Attributes[key] = value
+	}
+	return record, nil
+}
+
+// next_line returns the fields of the next line that is not blank or a comment.
+func (tr *textReader) next_line() ([]string, error) {
+	for tr.scanner.Scan() {
+		tr.line++
+		fields := strings.Fields(tr.scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		return fields, nil
+	}
+	if err := tr.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (tr *textReader) errorf(record string) error {
+	return fmt.Errorf("line %d: invalid %s record: %w", tr.line, record, ErrFormat)
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// movement orders
+//
+// Players write movement orders as a list of directions separated by dashes,
+// commas or spaces, "N-NE-NE-SE". A direction may start with a repeat count,
+// so "3NE" is the same as "NE-NE-NE". Directions are the compass names for the
+// orientation of the layout (see ParseDirection).
+//
+// Orders come from players, so an order can't expand to more than
+// MaxMoveSteps steps. Larger orders are rejected with ErrTooManySteps.
+
+// MaxMoveSteps is the most steps that one movement order can have.
+const MaxMoveSteps = 1000
+
+var (
+	// ErrBlocked is returned when a step enters a blocked hex.
+	ErrBlocked = errors.New("hex is blocked")
+	// ErrOutOfBounds is returned when a step leaves the map.
+	ErrOutOfBounds = errors.New("hex is out of bounds")
+	// ErrNoMovementPoints is returned when a step costs more than the movement points left.
+	ErrNoMovementPoints = errors.New("not enough movement points")
+	// ErrInvalidCost is returned when the cost of a step is zero or negative.
+	ErrInvalidCost = errors.New("step cost must be positive")
+	// ErrTooManySteps is returned when an order expands to more than MaxMoveSteps steps.
+	ErrTooManySteps = errors.New("too many steps")
+)
+
+// MoveStep is a single step in a movement order.
+type MoveStep struct {
+	Direction Direction
+	Token     string // the text that the step came from, "3NE"
+	Position  int    // the byte offset of the token in the orders
+}
+
+// Move is a step that has been taken.
+type Move struct {
+	MoveStep
+	Step     int // index of the step, starting at 0
+	From, To Hex
+	Cost     int // the cost of this step
+	Spent    int // the total cost of all the steps up to and including this one
+}
+
+// MoveError reports the step that failed.
+type MoveError struct {
+	Step     int    // index of the step, starting at 0
+	Token    string // the text that the step came from
+	Position int    // the byte offset of the token in the orders
+	Err      error
+}
+
+func (e *MoveError) Error() string {
+	return fmt.Sprintf("step %d (%q at %d): %v", e.Step+1, e.Token, e.Position, e.Err)
+}
+
+func (e *MoveError) Unwrap() error {
+	return e.Err
+}
+
+// MoveOptions are the checks made on each step.
+type MoveOptions struct {
+	// Blocked returns true if the hex can't be entered.
+	Blocked func(h Hex) bool
+
+	// InBounds returns false if the hex is off the map.
+	InBounds func(h Hex) bool
+
+	// Cost returns the movement points needed to step from one hex into its neighbor.
+	// If nil, every step costs 1. A cost that isn't positive stops the move with ErrInvalidCost.
+	Cost func(from, to Hex) int
+
+	// MovementPoints is the budget for the whole order. Zero means no limit.
+	MovementPoints int
+
+	// Validate is called after the other checks pass. A non-nil error stops the move.
+	Validate func(step int, from, to Hex) error
+}
+
+// ParseMoves returns the steps in the movement orders.
+func ParseMoves(orientation Orientation, orders string) ([]MoveStep, error) {
+	var steps []MoveStep
+	for _, token := range split_move_orders(orders) {
+		text := token.text
+
+		count, digits := 1, 0
+		for digits < len(text) && '0' <= text[digits] && text[digits] <= '9' {
+			digits++
+		}
+		if digits != 0 {
+			n, err := strconv.Atoi(text[:digits])
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, &MoveError{Step: len(steps), Token: text, Position: token.position, Err: ErrTooManySteps}
+			} else if err != nil || n == 0 {
+				return nil, &MoveError{Step: len(steps), Token: text, Position: token.position, Err: ErrSyntax}
+			}
+			count = n
+		}
+
+		direction, err := ParseDirection(orientation, text[digits:])
+		if err != nil {
+			return nil, &MoveError{Step: len(steps), Token: text, Position: token.position, Err: ErrInvalidDirection}
+		}
+
+		if count > MaxMoveSteps-len(steps) {
+			return nil, &MoveError{Step: len(steps), Token: text, Position: token.position, Err: ErrTooManySteps}
+		}
+		for i := 0; i < count; i++ {
+			steps = append(steps, MoveStep{Direction: direction, Token: text, Position: token.position})
+		}
+	}
+	return steps, nil
+}
+
+type move_token struct {
+	text     string
+	position int
+}
+
+// split_move_orders splits the orders on dashes, commas and white space.
+func split_move_orders(orders string) (tokens []move_token) {
+	start := -1
+	for i, r := range orders {
+		if r == '-' || r == ',' || unicode.IsSpace(r) {
+			if start != -1 {
+				tokens = append(tokens, move_token{text: orders[start:i], position: start})
+				start = -1
+			}
+		} else if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		tokens = append(tokens, move_token{text: orders[start:], position: start})
+	}
+	return tokens
+}
+
+// ExecuteMoves walks the steps from the start hex.
+// It returns the moves taken before any error, so the last move
+// (or the start hex, if there are no moves) is where the unit stopped.
+func ExecuteMoves(start Hex, steps []MoveStep, opts MoveOptions) ([]Move, error) {
+	var moves []Move
+	from, spent := start, 0
+	for i, step := range steps {
+		fail := func(err error) ([]Move, error) {
+			return moves, &MoveError{Step: i, Token: step.Token, Position: step.Position, Err: err}
+		}
+
+		to := hex_neighbor(from, int(step.Direction))
+		if opts.InBounds != nil && !opts.InBounds(to) {
+			return fail(ErrOutOfBounds)
+		} else if opts.Blocked != nil && opts.Blocked(to) {
+			return fail(ErrBlocked)
+		}
+		cost := 1
+		if opts.Cost != nil {
+			cost = opts.Cost(from, to)
+		}
+		if cost <= 0 {
+			return fail(ErrInvalidCost)
+		} else if opts.MovementPoints != 0 && spent+cost > opts.MovementPoints {
+			return fail(ErrNoMovementPoints)
+		}
+		if opts.Validate != nil {
+			if err := opts.Validate(i, from, to); err != nil {
+				return fail(err)
+			}
+		}
+
+		spent += cost
+		moves = append(moves, Move{MoveStep: step, Step: i, From: from, To: to, Cost: cost, Spent: spent})
+		from = to
+	}
+	return moves, nil
+}
+
+// RunMoves parses the orders using the orientation of the layout and walks them from the start hex.
+func RunMoves(layout Layout, start Hex, orders string, opts MoveOptions) ([]Move, error) {
+	steps, err := ParseMoves(layout.Orientation(), orders)
+	if err != nil {
+		return nil, err
+	}
+	return ExecuteMoves(start, steps, opts)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseMoves(t *testing.T) {
+	for _, tc := range []struct {
+		id     int
+		orders string
+		want   int // the number of steps
+		err    error
+	}{
+		{1, "N-NE-NE-SE", 4, nil},
+		{2, "3NE, s", 4, nil},
+		{3, "", 0, nil},
+		{4, "0N", 0, ErrSyntax},
+		{5, "NNE", 0, ErrInvalidDirection},
+		{6, "1000N", 1000, nil},
+		{7, "1001N", 0, ErrTooManySteps},
+		{8, "1000000000N", 0, ErrTooManySteps},
+		{9, "99999999999999999999N", 0, ErrTooManySteps},
+		{10, "999N 1N", 1000, nil},
+		{11, "999N 2N", 0, ErrTooManySteps},
+		{12, strings.Repeat("N ", 1001), 0, ErrTooManySteps},
+	} {
+		steps, err := ParseMoves(Flat(), tc.orders)
+		if tc.err != nil {
+			var me *MoveError
+			if !errors.Is(err, tc.err) || !errors.As(err, &me) {
+				t.Errorf("%d: %q: want %v: got %v", tc.id, tc.orders, tc.err, err)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%d: %q: want nil: got %v", tc.id, tc.orders, err)
+		} else if len(steps) != tc.want {
+			t.Errorf("%d: %q: want %d steps: got %d", tc.id, tc.orders, tc.want, len(steps))
+		}
+	}
+}
+
+func TestRunMoves(t *testing.T) {
+	layout := NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0))
+	errStop := errors.New("stop")
+	// "N-NE-NE-SE" from the origin goes through (0, -1, 1), (1, -2, 1), (2, -3, 1) to (3, -3, 0).
+	// The tokens start at bytes 0, 2, 5 and 8.
+	const orders = "N-NE-NE-SE"
+	for _, tc := range []struct {
+		id       int
+		opts     MoveOptions
+		moves    int // the number of moves taken
+		stop     Hex // where the unit stopped
+		spent    int
+		err      error
+		step     int // the step that failed
+		position int // the byte offset of the step that failed
+	}{
+		{1, MoveOptions{}, 4, NewHex(3, -3, 0), 4, nil, 0, 0},
+		{2, MoveOptions{Blocked: func(h Hex) bool { return h == NewHex(1, -2, 1) }}, 1, NewHex(0, -1, 1), 1, ErrBlocked, 1, 2},
+		{3, MoveOptions{InBounds: func(h Hex) bool { return h.Length() <= 2 }}, 2, NewHex(1, -2, 1), 2, ErrOutOfBounds, 2, 5},
+		{4, MoveOptions{MovementPoints: 3}, 3, NewHex(2, -3, 1), 3, ErrNoMovementPoints, 3, 8},
+		{5, MoveOptions{MovementPoints: 5, Cost: func(from, to Hex) int { return 2 }}, 2, NewHex(1, -2, 1), 4, ErrNoMovementPoints, 2, 5},
+		{6, MoveOptions{Validate: func(step int, from, to Hex) error {
+			if step == 3 {
+				return errStop
+			}
+			return nil
+		}}, 3, NewHex(2, -3, 1), 3, errStop, 3, 8},
+		{7, MoveOptions{Cost: func(from, to Hex) int { return 0 }}, 0, NewHex(0, 0, 0), 0, ErrInvalidCost, 0, 0},
+		{8, MoveOptions{Cost: func(from, to Hex) int {
+			if to == NewHex(2, -3, 1) {
+				return -5
+			}
+			return 1
+		}}, 2, NewHex(1, -2, 1), 2, ErrInvalidCost, 2, 5},
+	} {
+		moves, err := RunMoves(layout, NewHex(0, 0, 0), orders, tc.opts)
+		if len(moves) != tc.moves {
+			t.Errorf("%d: want %d moves: got %d", tc.id, tc.moves, len(moves))
+		}
+		stop, spent := NewHex(0, 0, 0), 0
+		if len(moves) != 0 {
+			stop, spent = moves[len(moves)-1].To, moves[len(moves)-1].Spent
+		}
+		if stop != tc.stop || spent != tc.spent {
+			t.Errorf("%d: want stop at %v having spent %d: got %v, %d", tc.id, tc.stop, tc.spent, stop, spent)
+		}
+		if tc.err == nil {
+			if err != nil {
+				t.Errorf("%d: want nil: got %v", tc.id, err)
+			}
+			continue
+		}
+		var me *MoveError
+		if !errors.Is(err, tc.err) || !errors.As(err, &me) {
+			t.Errorf("%d: want %v: got %v", tc.id, tc.err, err)
+		} else if me.Step != tc.step || me.Position != tc.position {
+			t.Errorf("%d: want step %d at %d: got step %d at %d", tc.id, tc.step, tc.position, me.Step, me.Position)
+		}
+	}
+
+	if moves, err := RunMoves(layout, NewHex(0, 0, 0), "N-X", MoveOptions{}); moves != nil || !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("bad orders: want %v: got %v, %v", ErrInvalidDirection, moves, err)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import "container/heap"
+
+// --------------------------------------------------------------------------------------------------------------------
+// pathfinding is from https://www.redblobgames.com/pathfinding/a-star/introduction.html
+//
+// The cost of a step is the cost of entering the new hex plus the cost of crossing
+// the edge between the two hexes. The A* heuristic is the hex distance to the goal,
+// so every step must cost at least 1. Steps that cost less are charged 1.
+//
+// The plane is infinite, so a search for a goal that can't be reached would
+// never end. Searches should be limited with InBounds or MaxCost, and every
+// search stops after expanding MaxExpansions hexes.
+
+// DefaultMaxExpansions is the limit on the hexes a search expands when PathOptions.MaxExpansions is zero.
+const DefaultMaxExpansions = 100_000
+
+// PathOptions controls the cost of moving between hexes.
+type PathOptions struct {
+	// HexCost returns the cost of entering the hex.
+	// Returning false marks the hex as impassable.
+	// If nil, every hex costs 1 to enter.
+	HexCost func(h Hex) (cost int, ok bool)
+
+	// EdgeCost returns the extra cost of crossing from a hex into its neighbor.
+	// Returning false marks the edge as blocked (rivers, cliffs).
+	// If nil, edges cost nothing to cross.
+	EdgeCost func(from, to Hex) (cost int, ok bool)
+
+	// InBounds returns false for hexes that the search must not enter.
+	// If nil, the search is not bounded.
+	InBounds func(h Hex) bool
+
+	// MaxCost is the movement budget for the search. Zero means no limit.
+	MaxCost int
+
+	// MaxExpansions is the most hexes the search will expand. Zero means DefaultMaxExpansions.
+	MaxExpansions int
+}
+
+func (opts PathOptions) max_expansions() int {
+	if opts.MaxExpansions == 0 {
+		return DefaultMaxExpansions
+	}
+	return opts.MaxExpansions
+}
+
+// step_cost returns the cost of moving from a hex into its neighbor.
+func (opts PathOptions) step_cost(from, to Hex) (int, bool) {
+	if opts.InBounds != nil && !opts.InBounds(to) {
+		return 0, false
+	}
+	cost := 1
+	if opts.HexCost != nil {
+		c, ok := opts.HexCost(to)
+		if !ok {
+			return 0, false
+		}
+		cost = c
+	}
+	if opts.EdgeCost != nil {
+		c, ok := opts.EdgeCost(from, to)
+		if !ok {
+			return 0, false
+		}
+		cost += c
+	}
+	return max(cost, 1), true
+}
+
+// FindPath returns the cheapest path from start to goal using A*.
+// The path includes both start and goal.
+// It returns false if there is no path within the budget, if the goal is
+// out of bounds or impassable, or if the search expands too many hexes.
+func FindPath(start, goal Hex, opts PathOptions) (path []Hex, cost int, ok bool) {
+	if goal != start {
+		if opts.InBounds != nil && !opts.InBounds(goal) {
+			return nil, 0, false
+		} else if opts.HexCost != nil {
+			if _, ok := opts.HexCost(goal); !ok {
+				return nil, 0, false
+			}
+		}
+	}
+
+	cameFrom := map[Hex]Hex{start: start}
+	costSoFar := map[Hex]int{start: 0}
+
+	frontier := &path_queue{}
+	heap.Push(frontier, path_item{hex: start, priority: hex_distance(start, goal)})
+	found, expansions := false, 0
+	for frontier.Len() != 0 {
+		current := heap.Pop(frontier).(path_item)
+		if current.hex == goal {
+			found = true
+			break
+		}
+		if current.cost != costSoFar[current.hex] {
+			// stale entry, we found a cheaper way here after it was queued
+			continue
+		}
+		if expansions++; expansions > opts.max_expansions() {
+			break
+		}
+		for direction := 0; direction < 6; direction++ {
+			next := hex_neighbor(current.hex, direction)
+			step, ok := opts.step_cost(current.hex, next)
+			if !ok {
+				continue
+			}
+			newCost := current.cost + step
+			if opts.MaxCost != 0 && newCost > opts.MaxCost {
+				continue
+			}
+			if oldCost, ok := costSoFar[next]; ok && oldCost <= newCost {
+				continue
+			}
+			costSoFar[next], cameFrom[next] = newCost, current.hex
+			heap.Push(frontier, path_item{hex: next, cost: newCost, priority: newCost + hex_distance(next, goal)})
+		}
+	}
+
+	if !found {
+		return nil, 0, false
+	}
+	cost = costSoFar[goal]
+	for h := goal; h != start; h = cameFrom[h] {
+		path = append(path, h)
+	}
+	path = append(path, start)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, cost, true
+}
+
+// Reachable returns every hex that can be reached from start, along with the
+// cheapest cost to reach it, using Dijkstra's algorithm.
+// The start hex is included with a cost of 0.
+// If the search expands too many hexes, it stops and returns the hexes found so far.
+func Reachable(start Hex, opts PathOptions) map[Hex]int {
+	costSoFar := map[Hex]int{start: 0}
+
+	frontier := &path_queue{}
+	heap.Push(frontier, path_item{hex: start})
+	settled := map[Hex]int{} // hexes whose cheapest cost is known
+	for frontier.Len() != 0 {
+		current := heap.Pop(frontier).(path_item)
+		if current.cost != costSoFar[current.hex] {
+			continue
+		}
+		if len(settled) == opts.max_expansions() {
+			break
+		}
+		settled[current.hex] = current.cost
+		for direction := 0; direction < 6; direction++ {
+			next := hex_neighbor(current.hex, direction)
+			step, ok := opts.step_cost(current.hex, next)
+			if !ok {
+				continue
+			}
+			newCost := current.cost + step
+			if opts.MaxCost != 0 && newCost > opts.MaxCost {
+				continue
+			}
+			if oldCost, ok := costSoFar[next]; ok && oldCost <= newCost {
+				continue
+			}
+			costSoFar[next] = newCost
+			heap.Push(frontier, path_item{hex: next, cost: newCost, priority: newCost})
+		}
+	}
+
+	return settled
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// priority queue
+
+type path_item struct {
+	hex      Hex
+	cost     int // cost from the start
+	priority int // cost plus the heuristic
+	sequence int // breaks ties so that results do not depend on heap internals
+}
+
+type path_queue struct {
+	items    []path_item
+	sequence int
+}
+
+func (pq *path_queue) Len() int {
+	return len(pq.items)
+}
+
+func (pq *path_queue) Less(i, j int) bool {
+	if pq.items[i].priority != pq.items[j].priority {
+		return pq.items[i].priority < pq.items[j].priority
+	}
+	return pq.items[i].sequence < pq.items[j].sequence
+}
+
+func (pq *path_queue) Swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+}
+
+func (pq *path_queue) Push(x any) {
+	item := x.(path_item)
+	item.sequence, pq.sequence = pq.sequence, pq.sequence+1
+	pq.items = append(pq.items, item)
+}
+
+func (pq *path_queue) Pop() any {
+	n := len(pq.items)
+	item := pq.items[n-1]
+	pq.items = pq.items[:n-1]
+	return item
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"testing"
+)
+
+func TestFindPath(t *testing.T) {
+	start, goal := NewHex(0, 0, 0), NewHex(3, -3, 0)
+	wall := NewRegion(NewRingRegion(goal, 1).Hexes()...)
+	for _, tc := range []struct {
+		id   int
+		opts PathOptions
+		ok   bool
+		cost int
+	}{
+		{1, PathOptions{}, true, 3},
+		{2, PathOptions{MaxCost: 2}, false, 0},
+		// an impassable goal on the unbounded plane
+		{3, PathOptions{HexCost: func(h Hex) (int, bool) { return 1, h != goal }}, false, 0},
+		// an out of bounds goal
+		{4, PathOptions{InBounds: func(h Hex) bool { return h.Length() < 3 }}, false, 0},
+		// a goal that is walled in on the unbounded plane
+		{5, PathOptions{HexCost: func(h Hex) (int, bool) { return 1, !wall.Contains(h) }, MaxExpansions: 10_000}, false, 0},
+		// a goal that is walled in, with the default limit
+		{6, PathOptions{HexCost: func(h Hex) (int, bool) { return 1, !wall.Contains(h) }}, false, 0},
+		{7, PathOptions{HexCost: func(h Hex) (int, bool) { return 2, true }}, true, 6},
+	} {
+		path, cost, ok := FindPath(start, goal, tc.opts)
+		if ok != tc.ok || cost != tc.cost {
+			t.Errorf("%d: want %v, %d: got %v, %d", tc.id, tc.ok, tc.cost, ok, cost)
+		} else if ok && (path[0] != start || path[len(path)-1] != goal || len(path) != 4) {
+			t.Errorf("%d: path: got %v", tc.id, path)
+		}
+	}
+
+	if path, cost, ok := FindPath(start, start, PathOptions{}); !ok || cost != 0 || len(path) != 1 {
+		t.Errorf("start is goal: got %v, %d, %v", path, cost, ok)
+	}
+}
+
+func TestReachable(t *testing.T) {
+	start := NewHex(0, 0, 0)
+	if got := Reachable(start, PathOptions{MaxCost: 2}); len(got) != 19 {
+		t.Errorf("max cost: want 19 hexes: got %d", len(got))
+	}
+	got := Reachable(start, PathOptions{MaxExpansions: 7})
+	if len(got) != 7 {
+		t.Errorf("max expansions: want 7 hexes: got %d", len(got))
+	}
+	for h, cost := range got {
+		if cost != h.Length() {
+			t.Errorf("max expansions: %v: want %d: got %d", h, h.Length(), cost)
+		}
+	}
+}
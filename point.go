@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"fmt"
+	"math"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// points
+//
+// Points are screen coordinates, with x to the right and y down. They are
+// also used as 2D vectors. Because y points down, positive angles rotate
+// clockwise on the screen.
+
+type Point struct {
+	X, Y float64
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%f, %f)", p.X, p.Y)
+}
+
+func NewPoint(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+// Add returns p + q.
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub returns p - q.
+func (p Point) Sub(q Point) Point {
+	return Point{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Scale returns p multiplied by f.
+func (p Point) Scale(f float64) Point {
+	return Point{X: p.X * f, Y: p.Y * f}
+}
+
+// Neg returns -p.
+func (p Point) Neg() Point {
+	return Point{X: -p.X, Y: -p.Y}
+}
+
+// Dot returns the dot product of p and q.
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross returns the z component of the cross product of p and q.
+// It is positive when q is clockwise from p on the screen.
+func (p Point) Cross(q Point) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Length returns the distance from the origin to p.
+func (p Point) Length() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+// Distance returns the distance from p to q.
+func (p Point) Distance(q Point) float64 {
+	return math.Hypot(q.X-p.X, q.Y-p.Y)
+}
+
+// Normalize returns p scaled to a length of 1. The zero vector is returned unchanged.
+func (p Point) Normalize() Point {
+	length := p.Length()
+	if length == 0 {
+		return p
+	}
+	return Point{X: p.X / length, Y: p.Y / length}
+}
+
+// Lerp returns the point t of the way from p to q.
+func (p Point) Lerp(q Point, t float64) Point {
+	return Point{X: lerp(p.X, q.X, t), Y: lerp(p.Y, q.Y, t)}
+}
+
+// Rotate returns p rotated around the origin by the angle, in radians.
+func (p Point) Rotate(radians float64) Point {
+	sin, cos := math.Sincos(radians)
+	return Point{X: p.X*cos - p.Y*sin, Y: p.X*sin + p.Y*cos}
+}
+
+// RotateAround returns p rotated around the center by the angle, in radians.
+func (p Point) RotateAround(center Point, radians float64) Point {
+	return p.Sub(center).Rotate(radians).Add(center)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPointArithmetic(t *testing.T) {
+	for _, tc := range []struct {
+		id       int
+		p, q     Point
+		add, sub Point
+		dot      float64
+		cross    float64
+	}{
+		{1, NewPoint(1, 2), NewPoint(3, 4), NewPoint(4, 6), NewPoint(-2, -2), 11, -2},
+		{2, NewPoint(1, 0), NewPoint(0, 1), NewPoint(1, 1), NewPoint(1, -1), 0, 1},
+		{3, NewPoint(0, 1), NewPoint(1, 0), NewPoint(1, 1), NewPoint(-1, 1), 0, -1},
+		{4, NewPoint(-2.5, 4), NewPoint(5, -8), NewPoint(2.5, -4), NewPoint(-7.5, 12), -44.5, 0},
+		{5, NewPoint(0, 0), NewPoint(7, -3), NewPoint(7, -3), NewPoint(-7, 3), 0, 0},
+	} {
+		if got := tc.p.Add(tc.q); got != tc.add {
+			t.Errorf("%d: %v.Add(%v): want %v: got %v", tc.id, tc.p, tc.q, tc.add, got)
+		}
+		if got := tc.p.Sub(tc.q); got != tc.sub {
+			t.Errorf("%d: %v.Sub(%v): want %v: got %v", tc.id, tc.p, tc.q, tc.sub, got)
+		}
+		if got := tc.p.Dot(tc.q); got != tc.dot {
+			t.Errorf("%d: %v.Dot(%v): want %v: got %v", tc.id, tc.p, tc.q, tc.dot, got)
+		}
+		if got := tc.p.Cross(tc.q); got != tc.cross {
+			t.Errorf("%d: %v.Cross(%v): want %v: got %v", tc.id, tc.p, tc.q, tc.cross, got)
+		}
+		// the cross product changes sign when the points are swapped, the dot product doesn't.
+		if tc.q.Cross(tc.p) != -tc.cross || tc.q.Dot(tc.p) != tc.dot {
+			t.Errorf("%d: %v, %v: want Cross to be antisymmetric and Dot symmetric", tc.id, tc.p, tc.q)
+		}
+	}
+}
+
+func TestPointLength(t *testing.T) {
+	for _, tc := range []struct {
+		id   int
+		p    Point
+		want float64
+	}{
+		{1, NewPoint(0, 0), 0},
+		{2, NewPoint(3, 4), 5},
+		{3, NewPoint(-3, 4), 5},
+		{4, NewPoint(0, -2), 2},
+		{5, NewPoint(1, 1), math.Sqrt2},
+	} {
+		if got := tc.p.Length(); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%d: %v.Length(): want %v: got %v", tc.id, tc.p, tc.want, got)
+		}
+		if got := NewPoint(10, -20).Add(tc.p).Distance(NewPoint(10, -20)); math.Abs(got-tc.want) > 1e-12 {
+			t.Errorf("%d: %v: Distance: want %v: got %v", tc.id, tc.p, tc.want, got)
+		}
+		if tc.want != 0 {
+			if got := tc.p.Normalize().Length(); math.Abs(got-1) > 1e-12 {
+				t.Errorf("%d: %v.Normalize(): want length 1: got %v", tc.id, tc.p, got)
+			}
+		} else if got := tc.p.Normalize(); got != tc.p {
+			t.Errorf("%d: %v.Normalize(): want the zero vector: got %v", tc.id, tc.p, got)
+		}
+	}
+}
+
+func TestPointLerp(t *testing.T) {
+	p, q := NewPoint(2, -4), NewPoint(10, 8)
+	for _, tc := range []struct {
+		id   int
+		t    float64
+		want Point
+	}{
+		{1, 0, p},
+		{2, 1, q},
+		{3, 0.5, NewPoint(6, 2)},
+		{4, 0.25, NewPoint(4, -1)},
+		{5, -0.5, NewPoint(-2, -10)},
+		{6, 1.5, NewPoint(14, 14)},
+	} {
+		if got := p.Lerp(q, tc.t); !points_near(got, tc.want) {
+			t.Errorf("%d: %v.Lerp(%v, %v): want %v: got %v", tc.id, p, q, tc.t, tc.want, got)
+		}
+	}
+}
+
+func TestPointRotate(t *testing.T) {
+	// y points down, so a quarter turn takes the x axis to the y axis, clockwise on the screen.
+	if got, want := NewPoint(1, 0).Rotate(math.Pi/2), NewPoint(0, 1); !points_near(got, want) {
+		t.Errorf("Rotate: want %v: got %v", want, got)
+	}
+	if got, want := NewPoint(5, 3).RotateAround(NewPoint(4, 3), math.Pi), NewPoint(3, 3); !points_near(got, want) {
+		t.Errorf("RotateAround: want %v: got %v", want, got)
+	}
+	if p := NewPoint(3, -7); math.Abs(p.Rotate(1.1).Length()-p.Length()) > 1e-12 {
+		t.Errorf("Rotate: want the length kept")
+	}
+}
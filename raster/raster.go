@@ -0,0 +1,155 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package raster draws hex maps onto images.
+package raster
+
+import (
+	"fmt"
+	"github.com/fogleman/gg"
+	"github.com/playbymail/hexes"
+	"golang.org/x/image/font"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Options controls how the map is drawn.
+type Options struct {
+	// Width and Height set the size of the canvas.
+	// If zero, the canvas is the size of the background image after scaling.
+	// If there is no background image, a zero Width or Height is sized to fit the hexes
+	// plus the margin on both sides, and the hexes are moved so the top left of their
+	// bounding box is at (Margin, Margin).
+	//
+	// Margin only applies to that auto-sizing. When there is a background image or
+	// both Width and Height are set, the hexes are drawn at their layout coordinates
+	// and Margin is ignored.
+	Width, Height int
+	Margin        float64
+
+	// Background is drawn before the hexes, scaled by BackgroundScale.
+	// The hexes are drawn in the coordinates of the scaled image.
+	Background      image.Image
+	BackgroundScale float64 // zero means 1
+
+	// Fill returns the fill color for the hex. If nil, or it returns nil, the hex is not filled.
+	Fill func(h hexes.Hex) color.Color
+
+	LineColor    color.Color // color of the hex outlines, nil means black
+	LineWidth    float64     // width of the hex outlines, zero means 1
+	CenterRadius float64     // radius of the dot drawn in the center of each hex, zero means no dot
+
+	// Label returns the text drawn in the center of the hex. If nil, hexes are not labeled.
+	Label      func(h hexes.Hex) string
+	LabelColor color.Color // nil means black
+	LabelFont  font.Face   // nil means the default font
+}
+
+// OffsetLabel returns a label function that prints the offset coordinates of the hex.
+func OffsetLabel(layout hexes.Layout) func(h hexes.Hex) string {
+	return func(h hexes.Hex) string {
+		column, row := layout.HexToOffset(h)
+		return fmt.Sprintf("%d, %d", column, row)
+	}
+}
+
+// Bounds returns the bounding box of the corners of the hexes.
+func Bounds(layout hexes.Layout, hv []hexes.Hex) (topLeft, bottomRight hexes.Point) {
+	if len(hv) == 0 {
+		return topLeft, bottomRight
+	}
+	topLeft = hexes.NewPoint(math.Inf(1), math.Inf(1))
+	bottomRight = hexes.NewPoint(math.Inf(-1), math.Inf(-1))
+	for _, h := range hv {
+		_, corners := layout.Points(h)
+		for _, pt := range corners {
+			topLeft.X, topLeft.Y = math.Min(topLeft.X, pt.X), math.Min(topLeft.Y, pt.Y)
+			bottomRight.X, bottomRight.Y = math.Max(bottomRight.X, pt.X), math.Max(bottomRight.Y, pt.Y)
+		}
+	}
+	return topLeft, bottomRight
+}
+
+// Draw returns a new image with the hexes drawn on it.
+func Draw(layout hexes.Layout, hv []hexes.Hex, opts Options) image.Image {
+	if opts.BackgroundScale == 0 {
+		opts.BackgroundScale = 1
+	}
+	if opts.LineColor == nil {
+		opts.LineColor = color.Black
+	}
+	if opts.LineWidth == 0 {
+		opts.LineWidth = 1
+	}
+	if opts.LabelColor == nil {
+		opts.LabelColor = color.Black
+	}
+
+	// size the canvas
+	var dx, dy float64 // translation applied to every point
+	width, height := opts.Width, opts.Height
+	if opts.Background != nil {
+		bounds := opts.Background.Bounds()
+		if width == 0 {
+			width = int(math.Ceil(float64(bounds.Dx()) * opts.BackgroundScale))
+		}
+		if height == 0 {
+			height = int(math.Ceil(float64(bounds.Dy()) * opts.BackgroundScale))
+		}
+	} else if width == 0 || height == 0 {
+		topLeft, bottomRight := Bounds(layout, hv)
+		dx, dy = opts.Margin-topLeft.X, opts.Margin-topLeft.Y
+		if width == 0 {
+			width = int(math.Ceil(bottomRight.X - topLeft.X + 2*opts.Margin))
+		}
+		if height == 0 {
+			height = int(math.Ceil(bottomRight.Y - topLeft.Y + 2*opts.Margin))
+		}
+	}
+
+	dc := gg.NewContext(max(width, 1), max(height, 1))
+	if opts.Background != nil {
+		dc.Push()
+		dc.Scale(opts.BackgroundScale, opts.BackgroundScale)
+		dc.DrawImage(opts.Background, 0, 0)
+		dc.Pop()
+	}
+	if opts.LabelFont != nil {
+		dc.SetFontFace(opts.LabelFont)
+	}
+
+	for _, h := range hv {
+		center, corners := layout.Points(h)
+		for i, pt := range corners {
+			if i == 0 {
+				dc.MoveTo(pt.X+dx, pt.Y+dy)
+			} else {
+				dc.LineTo(pt.X+dx, pt.Y+dy)
+			}
+		}
+		dc.ClosePath()
+		if opts.Fill != nil {
+			if fill := opts.Fill(h); fill != nil {
+				dc.SetColor(fill)
+				dc.FillPreserve()
+			}
+		}
+		dc.SetColor(opts.LineColor)
+		dc.SetLineWidth(opts.LineWidth)
+		dc.Stroke()
+
+		if opts.CenterRadius != 0 {
+			dc.DrawCircle(center.X+dx, center.Y+dy, opts.CenterRadius)
+			dc.SetColor(opts.LineColor)
+			dc.Fill()
+		}
+		if opts.Label != nil {
+			if label := opts.Label(h); label != "" {
+				dc.SetColor(opts.LabelColor)
+				dc.DrawStringAnchored(label, center.X+dx, center.Y+dy, 0.5, 0.5)
+			}
+		}
+	}
+
+	return dc.Image()
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package raster
+
+import (
+	"github.com/playbymail/hexes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+var red = color.RGBA{R: 0xff, A: 0xff}
+
+// is_red returns true if the pixel is the fill color.
+func is_red(img image.Image, x, y int) bool {
+	r, g, b, a := img.At(x, y).RGBA()
+	return r == 0xffff && g == 0 && b == 0 && a == 0xffff
+}
+
+// is_empty returns true if nothing was drawn on the pixel.
+func is_empty(img image.Image, x, y int) bool {
+	_, _, _, a := img.At(x, y).RGBA()
+	return a == 0
+}
+
+func TestBounds(t *testing.T) {
+	layout := hexes.NewFlatOddLayout(hexes.NewPoint(10, 10), hexes.NewPoint(0, 0))
+	topLeft, bottomRight := Bounds(layout, hexes.NewHexagonGrid[bool](1).Hexes())
+	h := 10 * math.Sqrt(3)
+	if topLeft.Distance(hexes.NewPoint(-25, -1.5*h)) > 1e-9 || bottomRight.Distance(hexes.NewPoint(25, 1.5*h)) > 1e-9 {
+		t.Errorf("Bounds: want (-25, %f)-(25, %f): got %v-%v", -1.5*h, 1.5*h, topLeft, bottomRight)
+	}
+	if topLeft, bottomRight := Bounds(layout, nil); topLeft != (hexes.Point{}) || bottomRight != (hexes.Point{}) {
+		t.Errorf("Bounds: want zero points for no hexes: got %v-%v", topLeft, bottomRight)
+	}
+}
+
+func TestDrawAutoSize(t *testing.T) {
+	layout := hexes.NewFlatOddLayout(hexes.NewPoint(10, 10), hexes.NewPoint(0, 0))
+	hv := hexes.NewHexagonGrid[bool](1).Hexes()
+	topLeft, bottomRight := Bounds(layout, hv)
+	fill := func(h hexes.Hex) color.Color { return red }
+	for _, margin := range []float64{0, 7, 20} {
+		img := Draw(layout, hv, Options{Margin: margin, Fill: fill})
+
+		// the canvas fits the hexes plus the margin on both sides.
+		want := image.Rect(0, 0, int(math.Ceil(bottomRight.X-topLeft.X+2*margin)), int(math.Ceil(bottomRight.Y-topLeft.Y+2*margin)))
+		if img.Bounds() != want {
+			t.Errorf("%v: want bounds %v: got %v", margin, want, img.Bounds())
+		}
+
+		// the hexes are moved so their bounding box starts at the margin.
+		dx, dy := margin-topLeft.X, margin-topLeft.Y
+		for _, h := range hv {
+			center := layout.HexToCenterPoint(h)
+			if x, y := int(center.X+dx), int(center.Y+dy); !is_red(img, x, y) {
+				t.Errorf("%v: %v: want the center at %d, %d filled", margin, h, x, y)
+			}
+		}
+		// nothing is drawn in the margin.
+		for _, pt := range [][2]int{{0, 0}, {want.Dx() - 1, want.Dy() - 1}, {int(margin) - 2, int(dy)}} {
+			if margin != 0 && !is_empty(img, pt[0], pt[1]) {
+				t.Errorf("%v: want %d, %d in the margin empty: got %v", margin, pt[0], pt[1], img.At(pt[0], pt[1]))
+			}
+		}
+	}
+
+	// a zero Width is sized to fit while the Height is kept.
+	if img := Draw(layout, hv, Options{Height: 200, Margin: 5}); img.Bounds() != image.Rect(0, 0, 60, 200) {
+		t.Errorf("auto width: want %v: got %v", image.Rect(0, 0, 60, 200), img.Bounds())
+	}
+}
+
+func TestDrawFixedSize(t *testing.T) {
+	// with both sizes set, the hexes stay at their layout coordinates and the margin is ignored.
+	layout := hexes.NewFlatOddLayout(hexes.NewPoint(10, 10), hexes.NewPoint(30, 40))
+	hv := []hexes.Hex{hexes.NewHex(0, 0, 0)}
+	fill := func(h hexes.Hex) color.Color { return red }
+	for _, margin := range []float64{0, 15} {
+		img := Draw(layout, hv, Options{Width: 100, Height: 80, Margin: margin, Fill: fill})
+		if img.Bounds() != image.Rect(0, 0, 100, 80) {
+			t.Errorf("%v: want bounds %v: got %v", margin, image.Rect(0, 0, 100, 80), img.Bounds())
+		}
+		if !is_red(img, 30, 40) {
+			t.Errorf("%v: want the hex centered at 30, 40", margin)
+		}
+		if !is_empty(img, 15, 15) {
+			t.Errorf("%v: want 15, 15 empty: got %v", margin, img.At(15, 15))
+		}
+	}
+}
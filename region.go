@@ -0,0 +1,294 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"slices"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// ranges, rings and spirals are from https://www.redblobgames.com/grids/hexagons/#range
+
+func hex_range(center Hex, n int) (results []Hex) {
+	for _, offset := range hexagon_shape(n) {
+		results = append(results, hex_add(center, offset))
+	}
+	return results
+}
+
+// hex_range_intersection returns the hexes that are within n steps of a and m steps of b.
+func hex_range_intersection(a Hex, n int, b Hex, m int) (results []Hex) {
+	qmin, qmax := max(a.q-n, b.q-m), min(a.q+n, b.q+m)
+	rmin, rmax := max(a.r-n, b.r-m), min(a.r+n, b.r+m)
+	smin, smax := max(a.s-n, b.s-m), min(a.s+n, b.s+m)
+	for q := qmin; q <= qmax; q++ {
+		for r := max(rmin, -q-smax); r <= min(rmax, -q-smin); r++ {
+			results = append(results, Hex{q: q, r: r, s: -q - r})
+		}
+	}
+	return results
+}
+
+func hex_ring(center Hex, radius int) (results []Hex) {
+	if radius <= 0 {
+		return []Hex{center}
+	}
+	hex := hex_add(center, hex_multiply(hex_direction(4), radius))
+	for i := 0; i < 6; i++ {
+		for j := 0; j < radius; j++ {
+			results = append(results, hex)
+			hex = hex_neighbor(hex, i)
+		}
+	}
+	return results
+}
+
+func hex_spiral(center Hex, radius int) (results []Hex) {
+	results = []Hex{center}
+	for k := 1; k <= radius; k++ {
+		results = append(results, hex_ring(center, k)...)
+	}
+	return results
+}
+
+// Range returns the hexes within n steps of the hex.
+func (h Hex) Range(n int) []Hex {
+	return hex_range(h, n)
+}
+
+// Ring returns the hexes exactly radius steps from the hex.
+// The ring starts at the southwest corner and goes counter-clockwise.
+func (h Hex) Ring(radius int) []Hex {
+	return hex_ring(h, radius)
+}
+
+// Spiral returns the hexes within radius steps of the hex,
+// starting with the hex itself and then each ring in turn.
+func (h Hex) Spiral(radius int) []Hex {
+	return hex_spiral(h, radius)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// regions
+
+// Region is a set of hexes.
+type Region map[Hex]struct{}
+
+// NewRegion returns a region containing the hexes.
+func NewRegion(hexes ...Hex) Region {
+	rg := make(Region, len(hexes))
+	for _, h := range hexes {
+		rg[h] = struct{}{}
+	}
+	return rg
+}
+
+// NewRangeRegion returns a region with the hexes within n steps of the center.
+func NewRangeRegion(center Hex, n int) Region {
+	return NewRegion(hex_range(center, n)...)
+}
+
+// NewRangeIntersectionRegion returns a region with the hexes that are within
+// n steps of a and within m steps of b.
+func NewRangeIntersectionRegion(a Hex, n int, b Hex, m int) Region {
+	return NewRegion(hex_range_intersection(a, n, b, m)...)
+}
+
+// NewRingRegion returns a region with the hexes exactly radius steps from the center.
+func NewRingRegion(center Hex, radius int) Region {
+	return NewRegion(hex_ring(center, radius)...)
+}
+
+// NewSpiralRegion returns a region with the hexes within radius steps of the center.
+// As a set, it is the same as NewRangeRegion.
+func NewSpiralRegion(center Hex, radius int) Region {
+	return NewRegion(hex_spiral(center, radius)...)
+}
+
+// NewLineRegion returns a region with the hexes on the line from a to b.
+func NewLineRegion(a, b Hex) Region {
+	return NewRegion(hex_linedraw(a, b)...)
+}
+
+// Add adds the hexes to the region.
+func (rg Region) Add(hexes ...Hex) {
+	for _, h := range hexes {
+		rg[h] = struct{}{}
+	}
+}
+
+// Remove removes the hexes from the region.
+func (rg Region) Remove(hexes ...Hex) {
+	for _, h := range hexes {
+		delete(rg, h)
+	}
+}
+
+// Contains returns true if the hex is in the region.
+func (rg Region) Contains(h Hex) bool {
+	_, ok := rg[h]
+	return ok
+}
+
+// Len returns the number of hexes in the region.
+func (rg Region) Len() int {
+	return len(rg)
+}
+
+// Hexes returns the hexes in the region sorted by r, then q.
+func (rg Region) Hexes() []Hex {
+	results := make([]Hex, 0, len(rg))
+	for h := range rg {
+		results = append(results, h)
+	}
+	slices.SortFunc(results, hex_compare)
+	return results
+}
+
+// hex_compare orders hexes by r, then q.
+func hex_compare(a, b Hex) int {
+	if a.r != b.r {
+		return a.r - b.r
+	}
+	return a.q - b.q
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// set operations
+
+// Union returns a new region with the hexes that are in either region.
+func (rg Region) Union(other Region) Region {
+	results := make(Region, len(rg)+len(other))
+	for h := range rg {
+		results[h] = struct{}{}
+	}
+	for h := range other {
+		results[h] = struct{}{}
+	}
+	return results
+}
+
+// Intersection returns a new region with the hexes that are in both regions.
+func (rg Region) Intersection(other Region) Region {
+	results := make(Region)
+	for h := range rg {
+		if other.Contains(h) {
+			results[h] = struct{}{}
+		}
+	}
+	return results
+}
+
+// Difference returns a new region with the hexes that are in this region but not the other.
+func (rg Region) Difference(other Region) Region {
+	results := make(Region)
+	for h := range rg {
+		if !other.Contains(h) {
+			results[h] = struct{}{}
+		}
+	}
+	return results
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// bounding boxes
+
+// CubeBounds is the range of each cube coordinate in a region.
+type CubeBounds struct {
+	MinQ, MaxQ int
+	MinR, MaxR int
+	MinS, MaxS int
+}
+
+// OffsetBounds is the range of offset coordinates in a region.
+type OffsetBounds struct {
+	MinColumn, MaxColumn int
+	MinRow, MaxRow       int
+}
+
+// CubeBounds returns the bounding box of the region in cube coordinates.
+// It returns false if the region is empty.
+func (rg Region) CubeBounds() (bounds CubeBounds, ok bool) {
+	for h := range rg {
+		if !ok {
+			bounds = CubeBounds{MinQ: h.q, MaxQ: h.q, MinR: h.r, MaxR: h.r, MinS: h.s, MaxS: h.s}
+			ok = true
+			continue
+		}
+		bounds.MinQ, bounds.MaxQ = min(bounds.MinQ, h.q), max(bounds.MaxQ, h.q)
+		bounds.MinR, bounds.MaxR = min(bounds.MinR, h.r), max(bounds.MaxR, h.r)
+		bounds.MinS, bounds.MaxS = min(bounds.MinS, h.s), max(bounds.MaxS, h.s)
+	}
+	return bounds, ok
+}
+
+// OffsetBounds returns the bounding box of the region in the offset coordinates of the layout.
+// It returns false if the region is empty.
+func (rg Region) OffsetBounds(layout Layout) (bounds OffsetBounds, ok bool) {
+	for h := range rg {
+		column, row := layout.HexToOffset(h)
+		if !ok {
+			bounds = OffsetBounds{MinColumn: column, MaxColumn: column, MinRow: row, MaxRow: row}
+			ok = true
+			continue
+		}
+		bounds.MinColumn, bounds.MaxColumn = min(bounds.MinColumn, column), max(bounds.MaxColumn, column)
+		bounds.MinRow, bounds.MaxRow = min(bounds.MinRow, row), max(bounds.MaxRow, row)
+	}
+	return bounds, ok
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// connected components
+
+// Components returns the connected parts of the region.
+// Two hexes are connected if they are neighbors.
+// The components are ordered by their first hex, using the same order as Hexes.
+func (rg Region) Components() (results []Region) {
+	seen := make(Region, len(rg))
+	for _, start := range rg.Hexes() {
+		if seen.Contains(start) {
+			continue
+		}
+		component := NewRegion(start)
+		seen[start] = struct{}{}
+		for queue := []Hex{start}; len(queue) != 0; queue = queue[1:] {
+			for direction := 0; direction < 6; direction++ {
+				neighbor := hex_neighbor(queue[0], direction)
+				if rg.Contains(neighbor) && !seen.Contains(neighbor) {
+					seen[neighbor] = struct{}{}
+					component[neighbor] = struct{}{}
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+		results = append(results, component)
+	}
+	return results
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// perimeter
+
+// Perimeter returns the edges between hexes in the region and hexes outside the region.
+// The edges are ordered by the hex inside the region, using the same order as Hexes, and then by direction.
+func (rg Region) Perimeter() (results []Edge) {
+	for _, h := range rg.Hexes() {
+		for direction := 0; direction < 6; direction++ {
+			if !rg.Contains(hex_neighbor(h, direction)) {
+				results = append(results, NewEdge(h, Direction(direction)))
+			}
+		}
+	}
+	return results
+}
+
+// Outline returns the screen coordinates of the line segments around the region.
+// The segments are in the same order as Perimeter.
+func (rg Region) Outline(layout Layout) (results [][2]Point) {
+	for _, edge := range rg.Perimeter() {
+		a, b := edge.Points(layout)
+		results = append(results, [2]Point{a, b})
+	}
+	return results
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRing(t *testing.T) {
+	center := NewHex(2, -5, 3)
+	for radius := 1; radius <= 5; radius++ {
+		ring := center.Ring(radius)
+		if len(ring) != 6*radius {
+			t.Errorf("%d: Ring: want %d hexes: got %d", radius, 6*radius, len(ring))
+			continue
+		}
+		// the ring starts at the southwest corner and walks each side in direction order.
+		if want := center.Add(hex_direction(4).Scale(radius)); ring[0] != want {
+			t.Errorf("%d: Ring: want start %v: got %v", radius, want, ring[0])
+		}
+		for i, h := range ring {
+			if got := center.DistanceTo(h); got != radius {
+				t.Errorf("%d: Ring[%d]: %v: want distance %d: got %d", radius, i, h, radius, got)
+			}
+			next := ring[(i+1)%len(ring)]
+			if want := h.Neighbor(Direction(i / radius)); next != want {
+				t.Errorf("%d: Ring[%d]: want %v after %v: got %v", radius, i+1, want, h, next)
+			}
+		}
+		if got := NewRegion(ring...).Len(); got != len(ring) {
+			t.Errorf("%d: Ring: want %d distinct hexes: got %d", radius, len(ring), got)
+		}
+	}
+	if got := center.Ring(0); len(got) != 1 || got[0] != center {
+		t.Errorf("0: Ring: want [%v]: got %v", center, got)
+	}
+}
+
+func TestSpiral(t *testing.T) {
+	center := NewHex(-3, 1, 2)
+	for radius := 0; radius <= 5; radius++ {
+		spiral := center.Spiral(radius)
+		if want := 3*radius*radius + 3*radius + 1; len(spiral) != want {
+			t.Errorf("%d: Spiral: want %d hexes: got %d", radius, want, len(spiral))
+			continue
+		}
+		if spiral[0] != center {
+			t.Errorf("%d: Spiral: want %v first: got %v", radius, center, spiral[0])
+		}
+		// each ring follows the one inside it.
+		for i := 1; i < len(spiral); i++ {
+			if center.DistanceTo(spiral[i]) < center.DistanceTo(spiral[i-1]) {
+				t.Errorf("%d: Spiral[%d]: %v is inside %v", radius, i, spiral[i], spiral[i-1])
+			}
+		}
+		region := NewRegion(spiral...)
+		if region.Len() != len(spiral) {
+			t.Errorf("%d: Spiral: want %d distinct hexes: got %d", radius, len(spiral), region.Len())
+		}
+		if want := NewRangeRegion(center, radius); !region_equal(region, want) {
+			t.Errorf("%d: Spiral: want %v: got %v", radius, want.Hexes(), region.Hexes())
+		}
+	}
+}
+
+// region_equal returns true if the regions hold the same hexes.
+func region_equal(a, b Region) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for h := range a {
+		if !b.Contains(h) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRegionSetOperations(t *testing.T) {
+	a := NewRangeRegion(NewHex(0, 0, 0), 3)
+	b := NewRangeRegion(NewHex(3, -1, -2), 2)
+	union, intersection, difference := a.Union(b), a.Intersection(b), a.Difference(b)
+	for _, h := range NewHexagonGrid[bool](8).Hexes() {
+		inA, inB := a.Contains(h), b.Contains(h)
+		if got := union.Contains(h); got != (inA || inB) {
+			t.Errorf("Union: %v: want %v: got %v", h, inA || inB, got)
+		}
+		if got := intersection.Contains(h); got != (inA && inB) {
+			t.Errorf("Intersection: %v: want %v: got %v", h, inA && inB, got)
+		}
+		if got := difference.Contains(h); got != (inA && !inB) {
+			t.Errorf("Difference: %v: want %v: got %v", h, inA && !inB, got)
+		}
+	}
+	if union.Len() != a.Len()+b.Len()-intersection.Len() {
+		t.Errorf("Union: want %d hexes: got %d", a.Len()+b.Len()-intersection.Len(), union.Len())
+	}
+	if want := NewRangeIntersectionRegion(NewHex(0, 0, 0), 3, NewHex(3, -1, -2), 2); !region_equal(intersection, want) {
+		t.Errorf("Intersection: want %v: got %v", want.Hexes(), intersection.Hexes())
+	}
+
+	// the operations return new regions and leave their operands alone.
+	if a.Len() != 37 || b.Len() != 19 {
+		t.Errorf("operands changed: want 37 and 19 hexes: got %d and %d", a.Len(), b.Len())
+	}
+	union.Add(NewHex(20, -20, 0))
+	if a.Contains(NewHex(20, -20, 0)) || b.Contains(NewHex(20, -20, 0)) {
+		t.Errorf("Union: shares storage with its operands")
+	}
+	if got := a.Difference(a); got.Len() != 0 {
+		t.Errorf("Difference with itself: want empty: got %v", got.Hexes())
+	}
+}
+
+func TestRegionComponents(t *testing.T) {
+	// two blobs with a gap of three hexes between them, and a single hex on its own.
+	left := NewRangeRegion(NewHex(-3, 0, 3), 1)
+	right := NewRangeRegion(NewHex(3, 0, -3), 1)
+	lone := NewRegion(NewHex(0, 4, -4))
+	region := left.Union(right).Union(lone)
+
+	components := region.Components()
+	if len(components) != 3 {
+		t.Fatalf("Components: want 3: got %d", len(components))
+	}
+	// the components are ordered by their first hex in r, q order.
+	for i, want := range []Region{left, right, lone} {
+		if !region_equal(components[i], want) {
+			t.Errorf("Components[%d]: want %v: got %v", i, want.Hexes(), components[i].Hexes())
+		}
+	}
+
+	// joining the blobs makes them one component.
+	region.Add(NewHex(-1, 0, 1), NewHex(0, 0, 0), NewHex(1, 0, -1))
+	if got := region.Components(); len(got) != 2 || got[0].Len() != 17 || got[1].Len() != 1 {
+		t.Errorf("joined Components: want 17 and 1 hexes: got %d components", len(got))
+	}
+	if got := NewRegion().Components(); got != nil {
+		t.Errorf("empty Components: want nil: got %v", got)
+	}
+}
+
+func TestRegionPerimeter(t *testing.T) {
+	// a hexagon of radius 2 with a hole in the middle has 30 edges on the outside and 6 around the hole.
+	region := NewRangeRegion(NewHex(0, 0, 0), 2)
+	region.Remove(NewHex(0, 0, 0))
+
+	perimeter := region.Perimeter()
+	if len(perimeter) != 36 {
+		t.Fatalf("Perimeter: want 36 edges: got %d", len(perimeter))
+	}
+	hole, previous := 0, Hex{}
+	for i, edge := range perimeter {
+		a, b := edge.Hexes()
+		if region.Contains(a) == region.Contains(b) {
+			t.Errorf("Perimeter[%d]: %v does not face out of the region", i, edge)
+		}
+		if a == NewHex(0, 0, 0) || b == NewHex(0, 0, 0) {
+			hole++
+		}
+		inside := a
+		if !region.Contains(a) {
+			inside = b
+		}
+		if i != 0 && hex_compare(previous, inside) > 0 {
+			t.Errorf("Perimeter[%d]: %v is out of order", i, edge)
+		}
+		previous = inside
+	}
+	if hole != 6 {
+		t.Errorf("Perimeter: want 6 edges around the hole: got %d", hole)
+	}
+
+	for name, layout := range test_layouts() {
+		outline := region.Outline(layout)
+		if len(outline) != len(perimeter) {
+			t.Errorf("%s: Outline: want %d segments: got %d", name, len(perimeter), len(outline))
+			continue
+		}
+		// the segments close into loops, so every end is shared by exactly two segments.
+		ends := make(map[[2]float64]int)
+		for _, segment := range outline {
+			for _, p := range segment {
+				ends[[2]float64{math.Round(p.X * 1e6), math.Round(p.Y * 1e6)}]++
+			}
+		}
+		for p, n := range ends {
+			if n != 2 {
+				t.Errorf("%s: Outline: %v ends %d segments", name, p, n)
+			}
+		}
+		// the hexes just to either side of the middle of a segment are one inside and one outside the region.
+		for i, segment := range outline {
+			middle := segment[0].Lerp(segment[1], 0.5)
+			along := segment[1].Sub(segment[0]).Scale(1e-3)
+			a := layout.PixelToHex(middle.Add(NewPoint(-along.Y, along.X)))
+			b := layout.PixelToHex(middle.Add(NewPoint(along.Y, -along.X)))
+			if region.Contains(a) == region.Contains(b) {
+				t.Errorf("%s: Outline[%d]: %v does not separate the region from the outside", name, i, segment)
+			}
+		}
+	}
+}
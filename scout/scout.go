@@ -0,0 +1,201 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package scout merges scouting reports from several sources.
+//
+// A report is what one source saw on one turn: a set of attributes, like
+// "terrain", for each hex. Sources often disagree, because they scouted on
+// different turns or because one of them is wrong. Each attribute has a
+// policy that picks the value to keep: the newest report, the most trusted
+// source, or neither, with the disagreement reported as a conflict.
+//
+// Only the newest report from each source counts. A source that reports a
+// hex as forest and later as swamp has seen it change, it doesn't disagree
+// with itself.
+package scout
+
+import (
+	"fmt"
+	"github.com/playbymail/hexes"
+	"sort"
+	"strings"
+)
+
+// Report is what one source saw on one turn.
+type Report struct {
+	Source string
+	Turn   int
+	Hexes  map[hexes.Hex]map[string]string // attributes of each hex
+}
+
+// Policy picks the value of an attribute when sources disagree.
+type Policy int
+
+const (
+	// Newest keeps the value from the newest report. Reports from the same
+	// turn are settled by trust.
+	Newest Policy = iota
+	// Trusted keeps the value from the most trusted source. Sources with the
+	// same trust are settled by the newest report.
+	Trusted
+	// Flag keeps the value only if every source agrees.
+	Flag
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Newest:
+		return "newest"
+	case Trusted:
+		return "trusted"
+	case Flag:
+		return "flag"
+	}
+	return "Policy(?)"
+}
+
+// Options controls how reports are merged.
+type Options struct {
+	// Policies is the policy for each attribute. Attributes that aren't listed use Default.
+	Policies map[string]Policy
+	Default  Policy
+
+	// Trust ranks the sources, higher is more trusted. Sources that aren't listed have a trust of 0.
+	Trust map[string]int
+}
+
+func (o Options) policy(attribute string) Policy {
+	if p, ok := o.Policies[attribute]; ok {
+		return p
+	}
+	return o.Default
+}
+
+// Claim is the value of an attribute as reported by one source.
+type Claim struct {
+	Source string
+	Turn   int
+	Value  string
+}
+
+// Conflict is an attribute of a hex that the policy couldn't settle.
+// The attribute is left out of the merged map.
+type Conflict struct {
+	Hex         hexes.Hex
+	Column, Row int // the offset coordinates of the hex
+	Attribute   string
+	Claims      []Claim // ordered by turn, then source
+}
+
+// String returns the conflict as "column, row attribute: value (source, turn n), ...".
+func (c Conflict) String() string {
+	claims := make([]string, len(c.Claims))
+	for i, claim := range c.Claims {
+		claims[i] = fmt.Sprintf("%s (%s, turn %d)", claim.Value, claim.Source, claim.Turn)
+	}
+	return fmt.Sprintf("%d, %d %s: %s", c.Column, c.Row, c.Attribute, strings.Join(claims, ", "))
+}
+
+// Result is the merged map and the attributes that couldn't be merged.
+type Result struct {
+	Hexes     map[hexes.Hex]map[string]string
+	Conflicts []Conflict // ordered by row, column and attribute
+}
+
+// Merge combines the reports. The layout gives the offset coordinates in the conflict report.
+func Merge(layout hexes.Layout, reports []Report, opts Options) *Result {
+	// keep the newest claim from each source for each attribute of each hex.
+	type key struct {
+		hex       hexes.Hex
+		attribute string
+	}
+	claims := make(map[key]map[string]Claim)
+	for _, report := range reports {
+		for h, attributes := range report.Hexes {
+			for attribute, value := range attributes {
+				k := key{hex: h, attribute: attribute}
+				if claims[k] == nil {
+					claims[k] = make(map[string]Claim)
+				}
+				if prior, ok := claims[k][report.Source]; !ok || prior.Turn <= report.Turn {
+					claims[k][report.Source] = Claim{Source: report.Source, Turn: report.Turn, Value: value}
+				}
+			}
+		}
+	}
+
+	result := &Result{Hexes: make(map[hexes.Hex]map[string]string)}
+	for k, sources := range claims {
+		cv := make([]Claim, 0, len(sources))
+		for _, claim := range sources {
+			cv = append(cv, claim)
+		}
+		sort.Slice(cv, func(i, j int) bool {
+			if cv[i].Turn != cv[j].Turn {
+				return cv[i].Turn < cv[j].Turn
+			}
+			return cv[i].Source < cv[j].Source
+		})
+
+		value, ok := settle(cv, opts.policy(k.attribute), opts.Trust)
+		if !ok {
+			column, row := layout.HexToOffset(k.hex)
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Hex:       k.hex,
+				Column:    column,
+				Row:       row,
+				Attribute: k.attribute,
+				Claims:    cv,
+			})
+			continue
+		}
+		if result.Hexes[k.hex] == nil {
+			result.Hexes[k.hex] = make(map[string]string)
+		}
+		result.Hexes[k.hex][k.attribute] = value
+	}
+
+	sort.Slice(result.Conflicts, func(i, j int) bool {
+		a, b := result.Conflicts[i], result.Conflicts[j]
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		} else if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Attribute < b.Attribute
+	})
+
+	return result
+}
+
+// settle returns the value the policy picks from the claims,
+// or false if the claims that are tied for first place disagree.
+func settle(claims []Claim, policy Policy, trust map[string]int) (string, bool) {
+	// rank returns the claim's place, lower is better.
+	var rank func(c Claim) [2]int
+	switch policy {
+	case Newest:
+		rank = func(c Claim) [2]int { return [2]int{-c.Turn, -trust[c.Source]} }
+	case Trusted:
+		rank = func(c Claim) [2]int { return [2]int{-trust[c.Source], -c.Turn} }
+	default:
+		rank = func(c Claim) [2]int { return [2]int{} }
+	}
+
+	best := rank(claims[0])
+	for _, c := range claims[1:] {
+		if r := rank(c); r[0] < best[0] || (r[0] == best[0] && r[1] < best[1]) {
+			best = r
+		}
+	}
+
+	value, found := "", false
+	for _, c := range claims {
+		if rank(c) != best {
+			continue
+		} else if found && c.Value != value {
+			return "", false
+		}
+		value, found = c.Value, true
+	}
+	return value, true
+}
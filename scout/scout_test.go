@@ -0,0 +1,118 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package scout
+
+import (
+	"github.com/playbymail/hexes"
+	"slices"
+	"testing"
+)
+
+// test_report returns a report of the terrain of one hex.
+func test_report(source string, turn int, h hexes.Hex, terrain string) Report {
+	return Report{Source: source, Turn: turn, Hexes: map[hexes.Hex]map[string]string{h: {"terrain": terrain}}}
+}
+
+func TestMergePolicies(t *testing.T) {
+	layout := hexes.NewFlatOddLayout(hexes.NewPoint(1, 1), hexes.NewPoint(0, 0))
+	h := layout.OffsetToHex(3, 2)
+	trust := map[string]int{"alice": 2, "bob": 1, "carol": 1}
+	for _, tc := range []struct {
+		id      int
+		policy  Policy
+		reports []Report
+		want    string // the merged value, or "" for a conflict
+		claims  []Claim
+	}{
+		// Newest keeps the latest report, and breaks ties on the turn by trust.
+		{1, Newest, []Report{test_report("alice", 1, h, "forest"), test_report("bob", 2, h, "swamp")}, "swamp", nil},
+		{2, Newest, []Report{test_report("alice", 2, h, "forest"), test_report("bob", 2, h, "swamp")}, "forest", nil},
+		{3, Newest, []Report{test_report("bob", 2, h, "swamp"), test_report("carol", 2, h, "swamp"), test_report("alice", 1, h, "forest")}, "swamp", nil},
+		{4, Newest, []Report{test_report("carol", 2, h, "hills"), test_report("bob", 2, h, "swamp"), test_report("alice", 1, h, "forest")}, "", []Claim{
+			{Source: "alice", Turn: 1, Value: "forest"},
+			{Source: "bob", Turn: 2, Value: "swamp"},
+			{Source: "carol", Turn: 2, Value: "hills"},
+		}},
+		// Trusted keeps the most trusted source, and breaks ties on trust by turn.
+		{5, Trusted, []Report{test_report("alice", 1, h, "forest"), test_report("bob", 2, h, "swamp")}, "forest", nil},
+		{6, Trusted, []Report{test_report("bob", 1, h, "forest"), test_report("carol", 3, h, "swamp")}, "swamp", nil},
+		{7, Trusted, []Report{test_report("dave", 9, h, "plains"), test_report("carol", 3, h, "swamp")}, "swamp", nil},
+		{8, Trusted, []Report{test_report("bob", 3, h, "forest"), test_report("carol", 3, h, "swamp")}, "", []Claim{
+			{Source: "bob", Turn: 3, Value: "forest"},
+			{Source: "carol", Turn: 3, Value: "swamp"},
+		}},
+		// Flag keeps a value only if every source agrees, whatever the turn or trust.
+		{9, Flag, []Report{test_report("alice", 1, h, "forest"), test_report("bob", 4, h, "forest")}, "forest", nil},
+		{10, Flag, []Report{test_report("bob", 4, h, "swamp"), test_report("alice", 1, h, "forest")}, "", []Claim{
+			{Source: "alice", Turn: 1, Value: "forest"},
+			{Source: "bob", Turn: 4, Value: "swamp"},
+		}},
+		// only the newest report from each source counts, so a source doesn't disagree with itself.
+		{11, Flag, []Report{test_report("bob", 1, h, "forest"), test_report("bob", 3, h, "swamp"), test_report("alice", 2, h, "swamp")}, "swamp", nil},
+		{12, Flag, []Report{test_report("bob", 3, h, "swamp"), test_report("bob", 1, h, "forest")}, "swamp", nil},
+		{13, Newest, []Report{test_report("alice", 5, h, "forest")}, "forest", nil},
+	} {
+		result := Merge(layout, tc.reports, Options{Default: tc.policy, Trust: trust})
+		got := result.Hexes[h]["terrain"]
+		if got != tc.want {
+			t.Errorf("%d: %v: want %q: got %q", tc.id, tc.policy, tc.want, got)
+		}
+		if tc.want != "" {
+			if len(result.Conflicts) != 0 {
+				t.Errorf("%d: %v: want no conflicts: got %v", tc.id, tc.policy, result.Conflicts)
+			}
+			continue
+		}
+		if len(result.Conflicts) != 1 {
+			t.Errorf("%d: %v: want 1 conflict: got %v", tc.id, tc.policy, result.Conflicts)
+			continue
+		}
+		c := result.Conflicts[0]
+		if c.Hex != h || c.Column != 3 || c.Row != 2 || c.Attribute != "terrain" || !slices.Equal(c.Claims, tc.claims) {
+			t.Errorf("%d: %v: want terrain at 3, 2 with %v: got %v", tc.id, tc.policy, tc.claims, c)
+		}
+		if _, ok := result.Hexes[h]; ok {
+			t.Errorf("%d: %v: want the hex left out: got %v", tc.id, tc.policy, result.Hexes[h])
+		}
+	}
+}
+
+func TestMergeOptions(t *testing.T) {
+	layout := hexes.NewPointyEvenLayout(hexes.NewPoint(1, 1), hexes.NewPoint(0, 0))
+	a, b := layout.OffsetToHex(4, 1), layout.OffsetToHex(2, 3)
+	reports := []Report{
+		{Source: "alice", Turn: 1, Hexes: map[hexes.Hex]map[string]string{
+			a: {"terrain": "forest", "owner": "red", "road": "yes"},
+			b: {"terrain": "hills", "owner": "red"},
+		}},
+		{Source: "bob", Turn: 2, Hexes: map[hexes.Hex]map[string]string{
+			a: {"terrain": "swamp", "owner": "blue", "road": "yes"},
+			b: {"terrain": "hills", "owner": "blue"},
+		}},
+	}
+	opts := Options{
+		Policies: map[string]Policy{"terrain": Trusted, "owner": Flag},
+		Default:  Newest,
+		Trust:    map[string]int{"alice": 1},
+	}
+	result := Merge(layout, reports, opts)
+	if got := result.Hexes[a]; got["terrain"] != "forest" || got["road"] != "yes" || len(got) != 2 {
+		t.Errorf("%v: want forest with a road: got %v", a, got)
+	}
+	if got := result.Hexes[b]; got["terrain"] != "hills" || len(got) != 1 {
+		t.Errorf("%v: want hills: got %v", b, got)
+	}
+
+	// the conflicts are ordered by row, then column, then attribute.
+	var got []string
+	for _, c := range result.Conflicts {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"4, 1 owner: red (alice, turn 1), blue (bob, turn 2)",
+		"2, 3 owner: red (alice, turn 1), blue (bob, turn 2)",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Conflicts: want %q: got %q", want, got)
+	}
+}
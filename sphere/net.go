@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package sphere
+
+import "github.com/playbymail/hexes"
+
+// --------------------------------------------------------------------------------------------------------------------
+// nets
+//
+// The net unfolds the icosahedron into the usual strip of five columns,
+// each with four triangles. The top triangles meet at five copies of the
+// north pole and the bottom triangles meet at five copies of the south pole.
+//
+// The net is a plane of hexes, so it can be drawn with a pointy layout.
+// Each face is placed on the plane with its corners on the lattice, which
+// puts every cell of the face on a hex of the plane. Cells on the edges of
+// the strip are cut apart by the unfolding and show up in more than one place.
+
+// the corners of the icosahedron on the net, in units of the frequency.
+var (
+	net_east      = hexes.NewHex(1, 0, -1)
+	net_northeast = hexes.NewHex(1, -1, 0)
+	net_southeast = hexes.NewHex(0, 1, -1)
+)
+
+// net_corners returns the corners of the face on the net, in the same order as the face's vertices.
+func net_corners(face int) [3]hexes.Hex {
+	k := face % 5
+	upper := net_east.Scale(k)          // the upper ring vertex on the left of column k
+	upper1 := net_east.Scale(k + 1)     // the upper ring vertex on the right of column k
+	lower := upper.Add(net_southeast)   // the lower ring vertex below column k
+	lower1 := upper1.Add(net_southeast) // the lower ring vertex on the right of that
+	north := upper.Add(net_northeast)   // the north pole above column k
+	south := lower.Add(net_southeast)   // the south pole below column k
+	switch face / 5 {
+	case 0:
+		return [3]hexes.Hex{north, upper, upper1}
+	case 1:
+		return [3]hexes.Hex{upper, lower, upper1}
+	case 2:
+		return [3]hexes.Hex{upper1, lower, lower1}
+	}
+	return [3]hexes.Hex{lower, south, lower1}
+}
+
+// net_hex returns the hex on the net for the face-local hex.
+func (g *Grid) net_hex(face int, h hexes.Hex) hexes.Hex {
+	corners := net_corners(face)
+	a := corners[0].Scale(g.frequency)
+	return a.Add(corners[1].Sub(corners[0]).Scale(h.Q())).Add(corners[2].Sub(corners[0]).Scale(h.R()))
+}
+
+// NetHex returns the hex on the net for the cell, on the face that owns the cell.
+func (g *Grid) NetHex(c Cell) hexes.Hex {
+	return g.net_hex(g.cells[c].face, g.cells[c].hex)
+}
+
+// Net returns every hex on the net and the cell drawn there.
+// Cells on the cut edges of the net appear more than once.
+func (g *Grid) Net() map[hexes.Hex]Cell {
+	net := make(map[hexes.Hex]Cell, len(g.lookup))
+	for fh, c := range g.lookup {
+		net[g.net_hex(fh.face, fh.hex)] = c
+	}
+	return net
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package sphere implements a hex grid that covers a sphere.
+//
+// The grid is a Goldberg polyhedron built on an icosahedron. Each of the
+// 20 triangular faces is cut into a triangle of hexes using the package's
+// cube coordinates: on a face with frequency n, the cells are the hexes
+// with q >= 0, r >= 0 and q + r <= n (the same shape as hexes.NewTriangleGrid).
+// The corner of the face at hex (0, 0, 0) is the face's first vertex,
+// (n, 0, -n) is its second vertex and (0, n, -n) is its third.
+//
+// Cells on the edges of a face are shared with the face next to it, and
+// the cells on the 12 corners of the icosahedron are shared by five faces.
+// Those twelve cells are pentagons; every other cell is a hexagon.
+// A grid with frequency n has 10 * n * n + 2 cells.
+package sphere
+
+import (
+	"errors"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"math"
+	"sort"
+)
+
+var (
+	ErrFrequency = errors.New("invalid frequency")
+)
+
+// Cell identifies a cell on the grid. Cells are numbered from 0 to Len()-1.
+type Cell int
+
+// Grid is a hex grid on a unit sphere.
+type Grid struct {
+	frequency int
+	vertices  [12]vec3
+	faces     [20][3]int
+	cells     []cell
+	lookup    map[face_hex]Cell
+}
+
+type cell struct {
+	position  vec3      // on the unit sphere
+	face      int       // the first face that contains the cell
+	hex       hexes.Hex // the face-local coordinates of the cell on that face
+	neighbors []Cell
+}
+
+type face_hex struct {
+	face int
+	hex  hexes.Hex
+}
+
+// NewGrid returns a grid where each edge of the icosahedron is divided into frequency steps.
+func NewGrid(frequency int) (*Grid, error) {
+	if frequency < 1 {
+		return nil, fmt.Errorf("%d: %w", frequency, ErrFrequency)
+	}
+	g := &Grid{
+		frequency: frequency,
+		lookup:    make(map[face_hex]Cell),
+	}
+	g.vertices, g.faces = icosahedron()
+
+	// a cell is identified by the weights of the icosahedron vertices
+	// that it is built from, which are the same on every face that shares it.
+	type weight struct{ vertex, weight int }
+	type cell_key [3]weight
+	keys := make(map[cell_key]Cell)
+
+	n := g.frequency
+	for face := range g.faces {
+		for _, h := range hexes.NewTriangleGrid[bool](n).Hexes() {
+			var key cell_key
+			for i, w := range [3]int{n - h.Q() - h.R(), h.Q(), h.R()} {
+				key[i] = weight{vertex: g.faces[face][i], weight: w}
+				if w == 0 {
+					key[i] = weight{vertex: len(g.vertices)}
+				}
+			}
+			sort.Slice(key[:], func(i, j int) bool {
+				return key[i].vertex < key[j].vertex
+			})
+
+			c, ok := keys[key]
+			if !ok {
+				c = Cell(len(g.cells))
+				keys[key] = c
+				g.cells = append(g.cells, cell{position: g.face_point(face, h), face: face, hex: h})
+			}
+			g.lookup[face_hex{face: face, hex: h}] = c
+		}
+	}
+
+	// the neighbors of a cell are the neighbors on each face that contains it
+	for fh, c := range g.lookup {
+		for direction := 0; direction < 6; direction++ {
+			neighbor, ok := g.lookup[face_hex{face: fh.face, hex: fh.hex.Neighbor(hexes.Direction(direction))}]
+			if ok && !contains(g.cells[c].neighbors, neighbor) {
+				g.cells[c].neighbors = append(g.cells[c].neighbors, neighbor)
+			}
+		}
+	}
+	for i := range g.cells {
+		neighbors := g.cells[i].neighbors
+		sort.Slice(neighbors, func(i, j int) bool {
+			return neighbors[i] < neighbors[j]
+		})
+	}
+
+	return g, nil
+}
+
+func contains(cells []Cell, c Cell) bool {
+	for _, e := range cells {
+		if e == c {
+			return true
+		}
+	}
+	return false
+}
+
+// icosahedron returns the vertices and faces of a unit icosahedron.
+// Vertex 0 is the north pole, 1 to 5 are the upper ring, 6 to 10 are
+// the lower ring, and 11 is the south pole. The upper ring starts at
+// longitude 0 and the lower ring is turned 36 degrees east of it.
+// Faces are listed with their vertices counter-clockwise when seen from
+// outside the sphere: five around the north pole, ten around the equator,
+// and five around the south pole.
+func icosahedron() (vertices [12]vec3, faces [20][3]int) {
+	lat := math.Atan(0.5) * 180 / math.Pi
+	vertices[0] = from_lat_lon(90, 0)
+	for k := 0; k < 5; k++ {
+		vertices[1+k] = from_lat_lon(lat, float64(72*k))
+		vertices[6+k] = from_lat_lon(-lat, float64(72*k+36))
+	}
+	vertices[11] = from_lat_lon(-90, 0)
+
+	for k := 0; k < 5; k++ {
+		u, u1 := 1+k, 1+(k+1)%5
+		l, l1 := 6+k, 6+(k+1)%5
+		faces[k] = [3]int{0, u, u1}
+		faces[5+k] = [3]int{u, l, u1}
+		faces[10+k] = [3]int{u1, l, l1}
+		faces[15+k] = [3]int{l, 11, l1}
+	}
+	return vertices, faces
+}
+
+// face_point returns the point on the sphere for the face-local hex.
+func (g *Grid) face_point(face int, h hexes.Hex) vec3 {
+	a, b, c := g.vertices[g.faces[face][0]], g.vertices[g.faces[face][1]], g.vertices[g.faces[face][2]]
+	n := float64(g.frequency)
+	wa, wb, wc := n-float64(h.Q()+h.R()), float64(h.Q()), float64(h.R())
+	return a.scale(wa).add(b.scale(wb)).add(c.scale(wc)).normalize()
+}
+
+// Frequency returns the number of steps along each edge of the icosahedron.
+func (g *Grid) Frequency() int {
+	return g.frequency
+}
+
+// Len returns the number of cells on the grid.
+func (g *Grid) Len() int {
+	return len(g.cells)
+}
+
+// Cell returns the cell at the face-local hex.
+// It returns false if the face or hex is not on the grid.
+func (g *Grid) Cell(face int, h hexes.Hex) (Cell, bool) {
+	c, ok := g.lookup[face_hex{face: face, hex: h}]
+	return c, ok
+}
+
+// FaceHex returns the face that owns the cell and the face-local hex of the cell.
+// Cells on the edge of a face are owned by the first face that contains them.
+func (g *Grid) FaceHex(c Cell) (face int, h hexes.Hex) {
+	return g.cells[c].face, g.cells[c].hex
+}
+
+// IsPentagon returns true if the cell is on a corner of the icosahedron.
+func (g *Grid) IsPentagon(c Cell) bool {
+	return len(g.cells[c].neighbors) == 5
+}
+
+// Neighbors returns the five or six cells next to the cell.
+func (g *Grid) Neighbors(c Cell) []Cell {
+	return append([]Cell(nil), g.cells[c].neighbors...)
+}
+
+// Distance returns the number of steps between the cells.
+func (g *Grid) Distance(a, b Cell) int {
+	if a == b {
+		return 0
+	}
+	distance := make([]int, len(g.cells))
+	for i := range distance {
+		distance[i] = -1
+	}
+	distance[a] = 0
+	queue := []Cell{a}
+	for len(queue) != 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range g.cells[c].neighbors {
+			if distance[neighbor] != -1 {
+				continue
+			}
+			distance[neighbor] = distance[c] + 1
+			if neighbor == b {
+				return distance[neighbor]
+			}
+			queue = append(queue, neighbor)
+		}
+	}
+	return -1 // not reached, the grid is connected
+}
+
+// Angle returns the great circle angle between the centers of the cells, in radians.
+// Multiply by the radius of the planet to get the distance along the surface.
+func (g *Grid) Angle(a, b Cell) float64 {
+	pa, pb := g.cells[a].position, g.cells[b].position
+	return math.Atan2(pa.cross(pb).length(), pa.dot(pb))
+}
+
+// LatLon returns the latitude and longitude of the center of the cell, in degrees.
+func (g *Grid) LatLon(c Cell) (lat, lon float64) {
+	return g.cells[c].position.lat_lon()
+}
+
+// Locate returns the cell that contains the latitude and longitude, in degrees.
+func (g *Grid) Locate(lat, lon float64) Cell {
+	p := from_lat_lon(lat, lon)
+
+	// find the face that the point projects onto. it is the face where the
+	// smallest barycentric coordinate is largest, which copes with points
+	// that sit on an edge.
+	bestFace, bestWeights, bestMin := 0, [3]float64{}, math.Inf(-1)
+	for face := range g.faces {
+		weights := g.barycentric(face, p)
+		if m := min(weights[0], weights[1], weights[2]); m > bestMin {
+			bestFace, bestWeights, bestMin = face, weights, m
+		}
+	}
+
+	// round the projection to the nearest hex on the face
+	n := float64(g.frequency)
+	q, r := n*bestWeights[1], n*bestWeights[2]
+	h := hexes.NewFractionalHex(q, r, -q-r).Round()
+	hq, hr := max(0, h.Q()), max(0, h.R())
+	for hq+hr > g.frequency {
+		if hq > hr {
+			hq--
+		} else {
+			hr--
+		}
+	}
+	c := g.lookup[face_hex{face: bestFace, hex: hexes.NewHex(hq, hr, -hq-hr)}]
+
+	// the projection isn't quite the same as the distance on the sphere,
+	// so walk to the neighbor closest to the point until there isn't one.
+	for {
+		best, closest := c, p.dot(g.cells[c].position)
+		for _, neighbor := range g.cells[c].neighbors {
+			if d := p.dot(g.cells[neighbor].position); d > closest {
+				best, closest = neighbor, d
+			}
+		}
+		if best == c {
+			return c
+		}
+		c = best
+	}
+}
+
+// barycentric returns the weights of the face's vertices for the point
+// where the ray through p crosses the plane of the face. The weights sum to 1.
+func (g *Grid) barycentric(face int, p vec3) [3]float64 {
+	a, b, c := g.vertices[g.faces[face][0]], g.vertices[g.faces[face][1]], g.vertices[g.faces[face][2]]
+	det := a.dot(b.cross(c))
+	weights := [3]float64{p.dot(b.cross(c)) / det, p.dot(c.cross(a)) / det, p.dot(a.cross(b)) / det}
+	sum := weights[0] + weights[1] + weights[2]
+	if sum <= 0 { // the point is on the far side of the sphere
+		return [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	}
+	for i := range weights {
+		weights[i] /= sum
+	}
+	return weights
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// vectors
+
+type vec3 struct {
+	x, y, z float64
+}
+
+func from_lat_lon(lat, lon float64) vec3 {
+	lat, lon = lat*math.Pi/180, lon*math.Pi/180
+	return vec3{x: math.Cos(lat) * math.Cos(lon), y: math.Cos(lat) * math.Sin(lon), z: math.Sin(lat)}
+}
+
+func (v vec3) lat_lon() (lat, lon float64) {
+	lat = math.Asin(max(-1, min(1, v.z))) * 180 / math.Pi
+	lon = math.Atan2(v.y, v.x) * 180 / math.Pi
+	return lat, lon
+}
+
+func (v vec3) add(b vec3) vec3 {
+	return vec3{x: v.x + b.x, y: v.y + b.y, z: v.z + b.z}
+}
+
+func (v vec3) scale(k float64) vec3 {
+	return vec3{x: v.x * k, y: v.y * k, z: v.z * k}
+}
+
+func (v vec3) dot(b vec3) float64 {
+	return v.x*b.x + v.y*b.y + v.z*b.z
+}
+
+func (v vec3) cross(b vec3) vec3 {
+	return vec3{x: v.y*b.z - v.z*b.y, y: v.z*b.x - v.x*b.z, z: v.x*b.y - v.y*b.x}
+}
+
+func (v vec3) length() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+func (v vec3) normalize() vec3 {
+	return v.scale(1 / v.length())
+}
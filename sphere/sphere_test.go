@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package sphere
+
+import (
+	"errors"
+	"github.com/playbymail/hexes"
+	"slices"
+	"testing"
+)
+
+var test_frequencies = []int{1, 2, 3, 5, 8}
+
+func TestNewGrid(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if _, err := NewGrid(n); !errors.Is(err, ErrFrequency) {
+			t.Errorf("%d: want %v: got %v", n, ErrFrequency, err)
+		}
+	}
+	for _, n := range test_frequencies {
+		g, err := NewGrid(n)
+		if err != nil {
+			t.Fatalf("%d: %v", n, err)
+		}
+		if g.Frequency() != n {
+			t.Errorf("%d: Frequency: got %d", n, g.Frequency())
+		}
+		if want := 10*n*n + 2; g.Len() != want {
+			t.Errorf("%d: Len: want %d: got %d", n, want, g.Len())
+		}
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	for _, n := range test_frequencies {
+		g, _ := NewGrid(n)
+		pentagons := 0
+		for c := Cell(0); int(c) < g.Len(); c++ {
+			neighbors := g.Neighbors(c)
+			switch len(neighbors) {
+			case 5:
+				pentagons++
+				if !g.IsPentagon(c) {
+					t.Errorf("%d: %d: five neighbors but not a pentagon", n, c)
+				}
+			case 6:
+				if g.IsPentagon(c) {
+					t.Errorf("%d: %d: six neighbors but a pentagon", n, c)
+				}
+			default:
+				t.Errorf("%d: %d: want 5 or 6 neighbors: got %v", n, c, neighbors)
+			}
+			for _, neighbor := range neighbors {
+				if neighbor == c {
+					t.Errorf("%d: %d: next to itself", n, c)
+				} else if !slices.Contains(g.Neighbors(neighbor), c) {
+					t.Errorf("%d: %d is next to %d, but not the other way", n, c, neighbor)
+				} else if d := g.Distance(c, neighbor); d != 1 {
+					t.Errorf("%d: Distance(%d, %d): want 1: got %d", n, c, neighbor, d)
+				}
+			}
+		}
+		if pentagons != 12 {
+			t.Errorf("%d: want 12 pentagons: got %d", n, pentagons)
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	for _, n := range test_frequencies {
+		g, _ := NewGrid(n)
+		for c := Cell(0); int(c) < g.Len(); c++ {
+			lat, lon := g.LatLon(c)
+			if got := g.Locate(lat, lon); got != c {
+				t.Errorf("%d: Locate(LatLon(%d)) = Locate(%f, %f): got %d", n, c, lat, lon, got)
+			}
+		}
+		// the poles are the first and last vertices of the icosahedron
+		if lat, _ := g.LatLon(g.Locate(90, 0)); lat != 90 {
+			t.Errorf("%d: north pole: want latitude 90: got %f", n, lat)
+		}
+	}
+}
+
+// TestNet checks that every cell has one home on the net, that the faces
+// agree on the cell for every hex they share, and that only cells on the
+// edges of faces are drawn more than once.
+func TestNet(t *testing.T) {
+	for _, n := range test_frequencies {
+		g, _ := NewGrid(n)
+		net := g.Net()
+
+		homes := make(map[hexes.Hex]Cell)
+		for c := Cell(0); int(c) < g.Len(); c++ {
+			h := g.NetHex(c)
+			if other, ok := homes[h]; ok {
+				t.Errorf("%d: cells %d and %d are both at %v", n, other, c, h)
+			}
+			homes[h] = c
+			if net[h] != c {
+				t.Errorf("%d: Net()[NetHex(%d)]: got %d", n, c, net[h])
+			}
+		}
+
+		placed := make(map[hexes.Hex]Cell)
+		for fh, c := range g.lookup {
+			h := g.net_hex(fh.face, fh.hex)
+			if other, ok := placed[h]; ok && other != c {
+				t.Errorf("%d: %v: faces disagree: %d and %d", n, h, other, c)
+			}
+			placed[h] = c
+		}
+
+		copies := make(map[Cell]int)
+		for h, c := range net {
+			copies[c]++
+			for _, neighbor := range h.Ring(1) {
+				if nc, ok := net[neighbor]; ok && nc != c && !slices.Contains(g.Neighbors(c), nc) {
+					t.Errorf("%d: %v and %v are next to each other on the net, but cells %d and %d aren't", n, h, neighbor, c, nc)
+				}
+			}
+		}
+		for c := Cell(0); int(c) < g.Len(); c++ {
+			_, h := g.FaceHex(c)
+			interior := h.Q() > 0 && h.R() > 0 && h.Q()+h.R() < n
+			if copies[c] == 0 || (interior && copies[c] != 1) {
+				t.Errorf("%d: cell %d (interior %v): drawn %d times", n, c, interior, copies[c])
+			}
+		}
+	}
+}
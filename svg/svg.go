@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package svg renders hex maps as standalone SVG documents.
+package svg
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Style controls how a single hex is drawn.
+type Style struct {
+	Fill        string  // fill color, empty means no fill
+	Stroke      string  // stroke color, empty means Options.Stroke
+	StrokeWidth float64 // stroke width, zero means Options.StrokeWidth
+	Label       string  // text drawn in the center of the hex
+	Icon        string  // URL of an image drawn in the center of the hex
+	Layer       string  // name of the group that the hex is drawn in
+}
+
+// Options controls how the map is drawn.
+type Options struct {
+	// Style returns the style for the hex. If nil, hexes are outlined and not filled.
+	Style func(h hexes.Hex) Style
+
+	// Labels adds the offset coordinates from the layout to hexes that have no label.
+	Labels bool
+
+	// Layers sets the order of the layer groups. Layers that are not listed are
+	// drawn after the listed ones, in the order they are first used.
+	// Hexes without a layer are drawn first.
+	Layers []string
+
+	Margin      float64 // space around the map, in pixels
+	Stroke      string  // default stroke color, empty means black
+	StrokeWidth float64 // default stroke width, zero means 1
+	FontSize    float64 // size of labels, zero means half the hex size
+	IconSize    float64 // width and height of icons, zero means the hex size
+}
+
+// Render writes the hexes as an SVG document.
+// Each hex is a group with an id derived from its cube coordinates.
+func Render(w io.Writer, layout hexes.Layout, hv []hexes.Hex, opts Options) error {
+	if opts.Stroke == "" {
+		opts.Stroke = "black"
+	}
+	if opts.StrokeWidth == 0 {
+		opts.StrokeWidth = 1
+	}
+	size := layout.Size()
+	if opts.FontSize == 0 {
+		opts.FontSize = math.Min(size.X, size.Y) / 2
+	}
+	if opts.IconSize == 0 {
+		opts.IconSize = math.Min(size.X, size.Y)
+	}
+
+	// group the hexes by layer, keeping the order of the input within each layer
+	layers := map[string][]hexes.Hex{}
+	order := []string{""}
+	for _, name := range opts.Layers {
+		if _, ok := layers[name]; !ok {
+			layers[name] = nil
+			order = append(order, name)
+		}
+	}
+	styles := make(map[hexes.Hex]Style, len(hv))
+	for _, h := range hv {
+		var style Style
+		if opts.Style != nil {
+			style = opts.Style(h)
+		}
+		styles[h] = style
+		if _, ok := layers[style.Layer]; !ok && style.Layer != "" {
+			order = append(order, style.Layer)
+		}
+		layers[style.Layer] = append(layers[style.Layer], h)
+	}
+
+	// the view box is the bounding box of every corner plus the margin
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, h := range hv {
+		_, corners := layout.Points(h)
+		for _, pt := range corners {
+			minX, minY = math.Min(minX, pt.X), math.Min(minY, pt.Y)
+			maxX, maxY = math.Max(maxX, pt.X), math.Max(maxY, pt.Y)
+		}
+	}
+	if len(hv) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	minX, minY = minX-opts.Margin, minY-opts.Margin
+	width, height := maxX+opts.Margin-minX, maxY+opts.Margin-minY
+
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	_, _ = fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"%s %s %s %s\" width=\"%s\" height=\"%s\">\n",
+		num(minX), num(minY), num(width), num(height), num(width), num(height))
+	for _, name := range order {
+		if len(layers[name]) == 0 {
+			continue
+		}
+		indent := ""
+		if name != "" {
+			_, _ = fmt.Fprintf(bw, "<g id=\"layer_%s\" class=\"layer\">\n", escape(name))
+			indent = "  "
+		}
+		for _, h := range layers[name] {
+			writeHex(bw, indent, layout, h, styles[h], opts)
+		}
+		if name != "" {
+			_, _ = fmt.Fprintf(bw, "</g>\n")
+		}
+	}
+	_, _ = fmt.Fprintf(bw, "</svg>\n")
+
+	return bw.Flush()
+}
+
+func writeHex(w io.Writer, indent string, layout hexes.Layout, h hexes.Hex, style Style, opts Options) {
+	center, corners := layout.Points(h)
+
+	fill, stroke, strokeWidth := style.Fill, style.Stroke, style.StrokeWidth
+	if fill == "" {
+		fill = "none"
+	}
+	if stroke == "" {
+		stroke = opts.Stroke
+	}
+	if strokeWidth == 0 {
+		strokeWidth = opts.StrokeWidth
+	}
+	label := style.Label
+	if label == "" && opts.Labels {
+		column, row := layout.HexToOffset(h)
+		label = fmt.Sprintf("%d, %d", column, row)
+	}
+
+	var points []string
+	for _, pt := range corners {
+		points = append(points, num(pt.X)+","+num(pt.Y))
+	}
+
+	_, _ = fmt.Fprintf(w, "%s<g id=\"%s\" class=\"hex\">\n", indent, ID(h))
+	_, _ = fmt.Fprintf(w, "%s  <polygon points=\"%s\" fill=\"%s\" stroke=\"%s\" stroke-width=\"%s\"/>\n",
+		indent, strings.Join(points, " "), escape(fill), escape(stroke), num(strokeWidth))
+	if style.Icon != "" {
+		_, _ = fmt.Fprintf(w, "%s  <image href=\"%s\" x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\"/>\n",
+			indent, escape(style.Icon), num(center.X-opts.IconSize/2), num(center.Y-opts.IconSize/2), num(opts.IconSize), num(opts.IconSize))
+	}
+	if label != "" {
+		_, _ = fmt.Fprintf(w, "%s  <text x=\"%s\" y=\"%s\" font-size=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\">%s</text>\n",
+			indent, num(center.X), num(center.Y), num(opts.FontSize), escape(label))
+	}
+	_, _ = fmt.Fprintf(w, "%s</g>\n", indent)
+}
+
+// ID returns the element id used for the hex.
+func ID(h hexes.Hex) string {
+	return fmt.Sprintf("hex_%d_%d_%d", h.Q(), h.R(), h.S())
+}
+
+// escape returns the string with XML special characters escaped.
+func escape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// num formats a coordinate with at most three decimal places.
+func num(f float64) string {
+	f = math.Round(f*1000) / 1000
+	if f == 0 {
+		f = 0 // drop the sign from negative zero
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/playbymail/hexes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// test_render renders four hexes in three layers, one of them not listed in Options.Layers.
+func test_render(t *testing.T) []byte {
+	layout := hexes.NewFlatOddLayout(hexes.NewPoint(10, 10), hexes.NewPoint(0, 0))
+	hv := []hexes.Hex{
+		hexes.NewHex(1, -1, 0),
+		hexes.NewHex(0, 0, 0),
+		hexes.NewHex(-1, 1, 0),
+		hexes.NewHex(0, 1, -1),
+	}
+	opts := Options{
+		Style: func(h hexes.Hex) Style {
+			switch h {
+			case hexes.NewHex(1, -1, 0):
+				return Style{Fill: "red", Icon: "tank.png", Layer: "units", Label: "A&B"}
+			case hexes.NewHex(-1, 1, 0):
+				return Style{Fill: "#2e8b57", Stroke: "green", StrokeWidth: 2.5, Layer: "roads"}
+			case hexes.NewHex(0, 1, -1):
+				return Style{Fill: "tan", Layer: "terrain"}
+			}
+			return Style{}
+		},
+		Labels: true,
+		Layers: []string{"terrain", "units"},
+		Margin: 5,
+	}
+	var buf bytes.Buffer
+	if err := Render(&buf, layout, hv, opts); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRender(t *testing.T) {
+	got := test_render(t)
+	want, err := os.ReadFile(filepath.Join("testdata", "render.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Render: want\n%s\ngot\n%s", want, got)
+	}
+
+	// the document is well formed, and every hex is a group with an id from its cube coordinates.
+	var doc struct {
+		Groups []struct {
+			ID     string `xml:"id,attr"`
+			Class  string `xml:"class,attr"`
+			Groups []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"g"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	var ids []string
+	for _, g := range doc.Groups {
+		if g.Class == "layer" {
+			ids = append(ids, g.ID)
+			for _, hg := range g.Groups {
+				ids = append(ids, hg.ID)
+			}
+		} else {
+			ids = append(ids, g.ID)
+		}
+	}
+	wantIDs := "hex_0_0_0 layer_terrain hex_0_1_-1 layer_units hex_1_-1_0 layer_roads hex_-1_1_0"
+	if got := strings.Join(ids, " "); got != wantIDs {
+		t.Errorf("Render: want groups %q: got %q", wantIDs, got)
+	}
+}
+
+func TestID(t *testing.T) {
+	for _, tc := range []struct {
+		id   int
+		h    hexes.Hex
+		want string
+	}{
+		{1, hexes.NewHex(0, 0, 0), "hex_0_0_0"},
+		{2, hexes.NewHex(3, -1, -2), "hex_3_-1_-2"},
+		{3, hexes.NewHex(-12, 20, -8), "hex_-12_20_-8"},
+	} {
+		if got := ID(tc.h); got != tc.want {
+			t.Errorf("%d: ID(%v): want %q: got %q", tc.id, tc.h, tc.want, got)
+		}
+	}
+}
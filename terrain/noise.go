@@ -0,0 +1,93 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package terrain
+
+import "math"
+
+// --------------------------------------------------------------------------------------------------------------------
+// noise
+//
+// The noise is value noise: a random value at each point of a square lattice,
+// smoothly blended between the points, added up over several octaves.
+// Everything after the first step is done in integer arithmetic, so the
+// same seed gives the same values on every platform.
+
+// fixed_point is the number of steps per pixel used when points are converted to integers.
+const fixed_point = 16
+
+// one is 1.0 in the 16-bit fractions used for blending.
+const one = 1 << 16
+
+// noise_field samples fractal value noise.
+type noise_field struct {
+	seed    uint64
+	spacing int64 // distance between lattice points of the first octave, in fixed point
+	octaves int
+}
+
+// quantize converts a screen coordinate to fixed point.
+func quantize(f float64) int64 {
+	return int64(math.Round(f * fixed_point))
+}
+
+// sample returns the noise at the fixed point coordinates, from 0 to 1000.
+func (n noise_field) sample(x, y int64) int {
+	var total, weight int64
+	spacing, amplitude := n.spacing, int64(1)<<(n.octaves-1)
+	for octave := 0; octave < n.octaves; octave++ {
+		total += amplitude * n.octave(uint64(octave), max(spacing, 1), x, y)
+		weight += amplitude
+		spacing, amplitude = spacing/2, max(amplitude/2, 1)
+	}
+	return int(total * 1000 / (weight * (one - 1)))
+}
+
+// octave returns the blended lattice value at the point, from 0 to one-1.
+func (n noise_field) octave(octave uint64, spacing, x, y int64) int64 {
+	ix, fx := floor_div(x, spacing)
+	iy, fy := floor_div(y, spacing)
+	sx, sy := smoothstep(fx*one/spacing), smoothstep(fy*one/spacing)
+
+	v00 := n.lattice(octave, ix, iy)
+	v10 := n.lattice(octave, ix+1, iy)
+	v01 := n.lattice(octave, ix, iy+1)
+	v11 := n.lattice(octave, ix+1, iy+1)
+	return blend(blend(v00, v10, sx), blend(v01, v11, sx), sy)
+}
+
+// lattice returns the random value at a lattice point, from 0 to one-1.
+func (n noise_field) lattice(octave uint64, ix, iy int64) int64 {
+	h := n.seed ^ octave*0x9e3779b97f4a7c15
+	h = mix(h ^ uint64(ix)*0xbf58476d1ce4e5b9)
+	h = mix(h ^ uint64(iy)*0x94d049bb133111eb)
+	return int64(h >> 48)
+}
+
+// mix is the finalizer from splitmix64.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// floor_div returns the quotient rounded down and the remainder, which is never negative.
+func floor_div(a, b int64) (int64, int64) {
+	q, r := a/b, a%b
+	if r < 0 {
+		q, r = q-1, r+b
+	}
+	return q, r
+}
+
+// smoothstep eases t, a fraction from 0 to one, with 3t² - 2t³.
+func smoothstep(t int64) int64 {
+	return t * t / one * (3*one - 2*t) / one
+}
+
+// blend returns the value between a and b at the fraction t.
+func blend(a, b, t int64) int64 {
+	return a + (b-a)*t/one
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package terrain
+
+import (
+	"container/heap"
+	"github.com/playbymail/hexes"
+	"sort"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// rivers
+//
+// Rivers run along the edges between hexes, from vertex to vertex.
+// The elevation of a vertex is the average of the three hexes that meet
+// there. Vertices that touch water or the edge of the world are outlets.
+//
+// Rain falls on every land vertex and runs downhill until it reaches an
+// outlet, so every river ends in the sea, a lake or off the edge of the map.
+// Pits and flat plateaus would trap the water, so they are filled first
+// with a priority flood: starting from the outlets, the land is visited
+// from the lowest vertex on the edge of the visited area, and a vertex
+// that is lower than the one it was reached from is raised to that level.
+// Water in a pit fills it up and spills over its lowest rim.
+//
+// Each vertex drains to the lowest of its neighbors that the flood visited
+// before it. The flow of an edge is all the rain that runs down it, so
+// tributaries add to the river they join.
+
+// RiverOptions controls how rivers are traced.
+type RiverOptions struct {
+	// Rain returns the rain that falls on the vertex. If nil, every land vertex gets 1.
+	Rain func(v hexes.Vertex) int
+
+	// MinFlow is the least flow that counts as a river. Zero means 8.
+	MinFlow int
+}
+
+// Rivers is the set of edges that carry a river.
+type Rivers struct {
+	flow       map[hexes.Edge]int
+	downstream map[hexes.Edge]hexes.Vertex // the vertex that the river on the edge flows toward
+}
+
+// Segment is one edge of a river, drawn from upstream to downstream.
+type Segment struct {
+	Edge     hexes.Edge
+	From, To hexes.Point
+	Flow     int
+}
+
+// drain is a vertex in the flood.
+type drain struct {
+	vertex     hexes.Vertex
+	land       bool
+	filled     int  // three times the elevation after pits are filled, -1 for outlets
+	order      int  // the order the flood visited the vertex, -1 until it is visited
+	queued     bool // the vertex has been added to the flood
+	downstream *drain
+	edge       hexes.Edge // the edge to the downstream vertex
+}
+
+// TraceRivers returns the rivers that drain the world.
+func TraceRivers(world *hexes.Grid[Hex], opts RiverOptions) *Rivers {
+	if opts.MinFlow == 0 {
+		opts.MinFlow = 8
+	}
+
+	// elevation returns three times the average elevation of the vertex,
+	// and false if the vertex touches water or the edge of the world.
+	elevation := func(v hexes.Vertex) (int, bool) {
+		sum := 0
+		for _, h := range v.Hexes() {
+			t, ok := world.Get(h)
+			if !ok || t.Water() {
+				return 0, false
+			}
+			sum += t.Elevation
+		}
+		return sum, true
+	}
+
+	// find the land vertices and the outlets next to them.
+	drains := make(map[hexes.Vertex]*drain)
+	flood := &drain_queue{}
+	for _, h := range world.Hexes() {
+		for _, v := range h.Vertices() {
+			if _, ok := drains[v]; ok {
+				continue
+			}
+			e, ok := elevation(v)
+			if !ok {
+				continue
+			}
+			drains[v] = &drain{vertex: v, land: true, filled: e, order: -1}
+			for _, neighbor := range v.Neighbors() {
+				if _, ok := drains[neighbor]; ok {
+					continue
+				} else if _, ok := elevation(neighbor); ok {
+					continue
+				}
+				outlet := &drain{vertex: neighbor, filled: -1, order: -1, queued: true}
+				drains[neighbor] = outlet
+				heap.Push(flood, outlet)
+			}
+		}
+	}
+
+	// flood the land from the outlets. each vertex drains to the lowest
+	// neighbor that was visited before it, which is never uphill once the
+	// pits are filled, and always leads to an outlet.
+	var visited []*drain
+	for flood.Len() != 0 {
+		d := heap.Pop(flood).(*drain)
+		d.order = len(visited)
+		visited = append(visited, d)
+		edges := d.vertex.Edges()
+		for i, neighbor := range d.vertex.Neighbors() {
+			n, ok := drains[neighbor]
+			if !ok {
+				continue
+			}
+			if !n.queued {
+				n.filled, n.queued = max(n.filled, d.filled), true
+				heap.Push(flood, n)
+			} else if d.land && n.order != -1 && (d.downstream == nil || n.filled < d.downstream.filled) {
+				d.downstream, d.edge = n, edges[i]
+			}
+		}
+	}
+
+	// visiting the vertices in the reverse of the flood order adds all
+	// the water from upstream before it is passed on.
+	flow := make(map[*drain]int, len(visited))
+	r := &Rivers{flow: make(map[hexes.Edge]int), downstream: make(map[hexes.Edge]hexes.Vertex)}
+	for i := len(visited) - 1; i >= 0; i-- {
+		d := visited[i]
+		if !d.land {
+			continue
+		}
+		rain := 1
+		if opts.Rain != nil {
+			rain = opts.Rain(d.vertex)
+		}
+		flow[d] += rain
+		flow[d.downstream] += flow[d]
+		if flow[d] >= opts.MinFlow {
+			r.flow[d.edge] = flow[d]
+			r.downstream[d.edge] = d.downstream.vertex
+		}
+	}
+	return r
+}
+
+// drain_queue is a priority queue of vertices, lowest first.
+// Vertices at the same level come out in the order they went in.
+type drain_queue struct {
+	items    []*drain
+	sequence []int
+	next     int
+}
+
+func (q *drain_queue) Len() int {
+	return len(q.items)
+}
+
+func (q *drain_queue) Less(i, j int) bool {
+	if q.items[i].filled != q.items[j].filled {
+		return q.items[i].filled < q.items[j].filled
+	}
+	return q.sequence[i] < q.sequence[j]
+}
+
+func (q *drain_queue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.sequence[i], q.sequence[j] = q.sequence[j], q.sequence[i]
+}
+
+func (q *drain_queue) Push(x any) {
+	q.items = append(q.items, x.(*drain))
+	q.sequence = append(q.sequence, q.next)
+	q.next++
+}
+
+func (q *drain_queue) Pop() any {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items, q.sequence = q.items[:n-1], q.sequence[:n-1]
+	return item
+}
+
+// Flow returns the flow along the edge, or zero if there is no river.
+func (r *Rivers) Flow(e hexes.Edge) int {
+	return r.flow[e]
+}
+
+// Crosses returns true if moving from the hex in the direction crosses a river.
+func (r *Rivers) Crosses(h hexes.Hex, direction hexes.Direction) bool {
+	return r.flow[hexes.NewEdge(h, direction)] != 0
+}
+
+// Downstream returns the vertex that the river on the edge flows toward.
+func (r *Rivers) Downstream(e hexes.Edge) (hexes.Vertex, bool) {
+	v, ok := r.downstream[e]
+	return v, ok
+}
+
+// Edges returns the edges that carry a river, ordered by hex and direction.
+func (r *Rivers) Edges() []hexes.Edge {
+	edges := make([]hexes.Edge, 0, len(r.flow))
+	for e := range r.flow {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i].Hex(), edges[j].Hex()
+		if a.R() != b.R() {
+			return a.R() < b.R()
+		} else if a.Q() != b.Q() {
+			return a.Q() < b.Q()
+		}
+		return edges[i].Direction() < edges[j].Direction()
+	})
+	return edges
+}
+
+// EdgeCost returns a function for PathOptions.EdgeCost that charges cost for crossing a river.
+func (r *Rivers) EdgeCost(cost int) func(from, to hexes.Hex) (int, bool) {
+	return func(from, to hexes.Hex) (int, bool) {
+		if direction, ok := from.DirectionTo(to); ok && r.Crosses(from, direction) {
+			return cost, true
+		}
+		return 0, true
+	}
+}
+
+// Segments returns the river edges as lines between the corners of the hexes.
+func (r *Rivers) Segments(layout hexes.Layout) []Segment {
+	var segments []Segment
+	for _, e := range r.Edges() {
+		a, b := e.Vertices()
+		if a == r.downstream[e] {
+			a, b = b, a
+		}
+		segments = append(segments, Segment{Edge: e, From: a.Point(layout), To: b.Point(layout), Flow: r.flow[e]})
+	}
+	return segments
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package terrain
+
+import (
+	"github.com/playbymail/hexes"
+	"testing"
+)
+
+// check_rivers_reach_water follows every river downstream and fails if one
+// stops before it reaches water or the edge of the world.
+func check_rivers_reach_water(t *testing.T, world *hexes.Grid[Hex], rivers *Rivers) {
+	t.Helper()
+	outlet := func(v hexes.Vertex) bool {
+		for _, h := range v.Hexes() {
+			if terrain, ok := world.Get(h); !ok || terrain.Water() {
+				return true
+			}
+		}
+		return false
+	}
+	edges := rivers.Edges()
+	for _, e := range edges {
+		v, ok := rivers.Downstream(e)
+		for steps := 0; ok && !outlet(v); steps++ {
+			if steps > len(edges) {
+				t.Fatalf("%v: river loops at %v", e, v)
+			}
+			ok = false
+			for _, next := range v.Edges() {
+				if to, found := rivers.Downstream(next); found && to != v {
+					v, ok = to, true
+					break
+				}
+			}
+		}
+		if !ok {
+			t.Errorf("%v: river stops at %v", e, v)
+		}
+	}
+}
+
+func TestRiversReachWater(t *testing.T) {
+	layout := hexes.NewFlatEvenLayout(hexes.NewPoint(10, 10), hexes.NewPoint(0, 0))
+	for _, seed := range []int64{1, 2, 3, 42} {
+		world := Generate(layout, hexes.NewRangeRegion(hexes.NewHex(0, 0, 0), 20), Options{Seed: seed, EdgeFalloff: 3})
+		rivers := TraceRivers(world, RiverOptions{MinFlow: 1})
+		if len(rivers.Edges()) == 0 {
+			t.Fatalf("seed %d: want rivers: got none", seed)
+		}
+		check_rivers_reach_water(t, world, rivers)
+	}
+}
+
+// TestRiversFillPits checks that water spills out of a pit that is ringed by higher land.
+func TestRiversFillPits(t *testing.T) {
+	center := hexes.NewHex(0, 0, 0)
+	world := hexes.NewGrid[Hex](center.Range(3)...)
+	for _, h := range world.Hexes() {
+		switch center.DistanceTo(h) {
+		case 0:
+			world.Set(h, Hex{Elevation: 100})
+		case 1:
+			world.Set(h, Hex{Elevation: 900})
+		case 2:
+			world.Set(h, Hex{Elevation: 600})
+		default:
+			world.Set(h, Hex{Ocean: true})
+		}
+	}
+	rivers := TraceRivers(world, RiverOptions{MinFlow: 1})
+	check_rivers_reach_water(t, world, rivers)
+
+	// every vertex of the pit drains, so the rivers leave it.
+	for _, v := range center.Vertices() {
+		drained := false
+		for _, e := range v.Edges() {
+			if to, ok := rivers.Downstream(e); ok && to != v {
+				drained = true
+			}
+		}
+		if !drained {
+			t.Errorf("%v: want a river out of the pit: got none", v)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package terrain generates worlds from a seed.
+//
+// Elevation and moisture come from noise sampled at the center of each hex,
+// so the same seed gives the same features no matter how the region is cut.
+// Water is everything at or below sea level. Water that touches the edge of
+// the region is ocean and the rest is lakes. Land is given a biome from a
+// table of elevation and moisture ranges.
+//
+// The generator only uses integer arithmetic once the hex centers have been
+// rounded to a fixed point grid, so a seed and a shape give the same world
+// on every platform.
+package terrain
+
+import (
+	"github.com/playbymail/hexes"
+)
+
+// Hex is the generated terrain of a single hex.
+type Hex struct {
+	Elevation int // 0 to 1000
+	Moisture  int // 0 to 1000
+	Biome     string
+	Ocean     bool // water that is connected to the edge of the region
+	Lake      bool // water that isn't connected to the edge of the region
+	Coast     bool // land next to the ocean
+}
+
+// Water returns true if the hex is ocean or lake.
+func (h Hex) Water() bool {
+	return h.Ocean || h.Lake
+}
+
+// Biome is a row in the biome table. Land hexes get the first biome whose
+// elevation and moisture ranges, inclusive, contain the hex.
+type Biome struct {
+	Name                       string
+	MinElevation, MaxElevation int
+	MinMoisture, MaxMoisture   int
+}
+
+// DefaultBiomes is the biome table used when Options.Biomes is nil.
+var DefaultBiomes = []Biome{
+	{Name: "snow", MinElevation: 850, MaxElevation: 1000, MinMoisture: 500, MaxMoisture: 1000},
+	{Name: "tundra", MinElevation: 850, MaxElevation: 1000, MinMoisture: 0, MaxMoisture: 499},
+	{Name: "taiga", MinElevation: 700, MaxElevation: 849, MinMoisture: 600, MaxMoisture: 1000},
+	{Name: "shrubland", MinElevation: 700, MaxElevation: 849, MinMoisture: 0, MaxMoisture: 599},
+	{Name: "rain forest", MinElevation: 0, MaxElevation: 699, MinMoisture: 800, MaxMoisture: 1000},
+	{Name: "forest", MinElevation: 0, MaxElevation: 699, MinMoisture: 500, MaxMoisture: 799},
+	{Name: "grassland", MinElevation: 0, MaxElevation: 699, MinMoisture: 250, MaxMoisture: 499},
+	{Name: "desert", MinElevation: 0, MaxElevation: 699, MinMoisture: 0, MaxMoisture: 249},
+}
+
+// Options controls the generator. The zero value of each field picks a default.
+type Options struct {
+	Seed int64
+
+	// Scale is the size of the largest features, in screen pixels. Zero means 8 hexes across.
+	Scale float64
+
+	// Octaves is the number of layers of noise. Each layer has features half the size
+	// and half the height of the one before. Zero means 4.
+	Octaves int
+
+	// SeaLevel is the highest elevation that is under water. Zero means 400.
+	// Use a negative number for a world with no water.
+	SeaLevel int
+
+	// EdgeFalloff lowers the land within that many hexes of the edge of the region,
+	// which surrounds the world with ocean. Zero means no falloff.
+	EdgeFalloff int
+
+	// Biomes is the biome table for land hexes. Nil means DefaultBiomes.
+	Biomes []Biome
+
+	// OceanBiome and LakeBiome are the biomes for water hexes. Empty means "ocean" and "lake".
+	OceanBiome, LakeBiome string
+}
+
+// MaxOctaves is the most layers of noise. Past this the features are smaller
+// than the fixed point grid and the weights would overflow.
+const MaxOctaves = 16
+
+// the salts keep the elevation and moisture noise independent of each other.
+const (
+	elevation_salt = 0x656c6576
+	moisture_salt  = 0x6d6f6973
+)
+
+// Generate returns the terrain for every hex in the region.
+func Generate(layout hexes.Layout, region hexes.Region, opts Options) *hexes.Grid[Hex] {
+	// the world stays put when the camera moves.
+	layout = hexes.WithoutCamera(layout)
+
+	if opts.Scale == 0 {
+		size := layout.Size()
+		opts.Scale = 8 * 2 * max(size.X, size.Y)
+	}
+	if opts.Octaves <= 0 {
+		opts.Octaves = 4
+	} else if opts.Octaves > MaxOctaves {
+		opts.Octaves = MaxOctaves
+	}
+	if opts.SeaLevel == 0 {
+		opts.SeaLevel = 400
+	}
+	if opts.Biomes == nil {
+		opts.Biomes = DefaultBiomes
+	}
+	if opts.OceanBiome == "" {
+		opts.OceanBiome = "ocean"
+	}
+	if opts.LakeBiome == "" {
+		opts.LakeBiome = "lake"
+	}
+
+	spacing := max(quantize(opts.Scale), 1)
+	elevation := noise_field{seed: mix(uint64(opts.Seed) ^ elevation_salt), spacing: spacing, octaves: opts.Octaves}
+	moisture := noise_field{seed: mix(uint64(opts.Seed) ^ moisture_salt), spacing: spacing, octaves: opts.Octaves}
+
+	hv := region.Hexes()
+	grid := hexes.NewGrid[Hex](hv...)
+
+	var edge map[hexes.Hex]int
+	if opts.EdgeFalloff > 0 {
+		edge = edge_distances(region, hv)
+	}
+
+	for _, h := range hv {
+		p := layout.HexToCenterPoint(h)
+		x, y := quantize(p.X), quantize(p.Y)
+		t := Hex{Elevation: elevation.sample(x, y), Moisture: moisture.sample(x, y)}
+		if d, ok := edge[h]; ok && d < opts.EdgeFalloff {
+			t.Elevation = t.Elevation * d / opts.EdgeFalloff
+		}
+		grid.Set(h, t)
+	}
+
+	// water that can be reached from the edge of the region without
+	// crossing land is ocean, and the rest is lakes.
+	var queue []hexes.Hex
+	for _, h := range hv {
+		if t, _ := grid.Get(h); t.Elevation <= opts.SeaLevel && on_edge(region, h) {
+			t.Ocean = true
+			grid.Set(h, t)
+			queue = append(queue, h)
+		}
+	}
+	for len(queue) != 0 {
+		h := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range grid.Neighbors(h) {
+			if t, _ := grid.Get(neighbor); t.Elevation <= opts.SeaLevel && !t.Ocean {
+				t.Ocean = true
+				grid.Set(neighbor, t)
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	for _, h := range hv {
+		t, _ := grid.Get(h)
+		switch {
+		case t.Ocean:
+			t.Biome = opts.OceanBiome
+		case t.Elevation <= opts.SeaLevel:
+			t.Lake, t.Biome = true, opts.LakeBiome
+		default:
+			t.Biome = classify(opts.Biomes, t.Elevation, t.Moisture)
+			for _, neighbor := range grid.Neighbors(h) {
+				if n, _ := grid.Get(neighbor); n.Ocean {
+					t.Coast = true
+				}
+			}
+		}
+		grid.Set(h, t)
+	}
+
+	return grid
+}
+
+// classify returns the name of the first biome that contains the elevation and moisture,
+// or an empty string if there isn't one.
+func classify(biomes []Biome, elevation, moisture int) string {
+	for _, b := range biomes {
+		if b.MinElevation <= elevation && elevation <= b.MaxElevation && b.MinMoisture <= moisture && moisture <= b.MaxMoisture {
+			return b.Name
+		}
+	}
+	return ""
+}
+
+// on_edge returns true if the hex has a neighbor outside the region.
+func on_edge(region hexes.Region, h hexes.Hex) bool {
+	for direction := 0; direction < 6; direction++ {
+		if !region.Contains(h.Neighbor(hexes.Direction(direction))) {
+			return true
+		}
+	}
+	return false
+}
+
+// edge_distances returns the number of steps from each hex to the edge of the region.
+// Hexes on the edge are 0 steps away.
+func edge_distances(region hexes.Region, hv []hexes.Hex) map[hexes.Hex]int {
+	distances := make(map[hexes.Hex]int, len(hv))
+	var queue []hexes.Hex
+	for _, h := range hv {
+		if on_edge(region, h) {
+			distances[h] = 0
+			queue = append(queue, h)
+		}
+	}
+	for len(queue) != 0 {
+		h := queue[0]
+		queue = queue[1:]
+		for direction := 0; direction < 6; direction++ {
+			neighbor := h.Neighbor(hexes.Direction(direction))
+			if _, ok := distances[neighbor]; !ok && region.Contains(neighbor) {
+				distances[neighbor] = distances[h] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return distances
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package terrain
+
+import (
+	"fmt"
+	"github.com/playbymail/hexes"
+	"hash/fnv"
+	"testing"
+)
+
+// checksum returns a hash of every generated hex, in grid order.
+func checksum(world *hexes.Grid[Hex]) uint64 {
+	hash := fnv.New64a()
+	for _, h := range world.Hexes() {
+		t, _ := world.Get(h)
+		fmt.Fprintf(hash, "%v %d %d %q %t %t %t\n", h, t.Elevation, t.Moisture, t.Biome, t.Ocean, t.Lake, t.Coast)
+	}
+	return hash.Sum64()
+}
+
+// TestGenerateGolden pins the worlds generated from fixed seeds, so a change
+// to the noise or the rounding that would move every map is caught.
+func TestGenerateGolden(t *testing.T) {
+	layout := hexes.NewFlatEvenLayout(hexes.NewPoint(10, 10), hexes.NewPoint(0, 0))
+	region := hexes.NewRangeRegion(hexes.NewHex(0, 0, 0), 12)
+	for _, tc := range []struct {
+		id   int
+		opts Options
+		want uint64
+	}{
+		{1, Options{Seed: 1}, 0x6aed4dc07ad15b38},
+		{2, Options{Seed: 42, EdgeFalloff: 3}, 0x659db1c6c1c35649},
+		{3, Options{Seed: -7, Octaves: 6, SeaLevel: -1}, 0x1e2507c1b029cd38},
+	} {
+		if got := checksum(Generate(layout, region, tc.opts)); got != tc.want {
+			t.Errorf("%d: checksum: want %#x: got %#x", tc.id, tc.want, got)
+		}
+	}
+}
+
+// TestGenerateOctaves checks that octaves out of range are clamped instead of panicking.
+func TestGenerateOctaves(t *testing.T) {
+	layout := hexes.NewFlatEvenLayout(hexes.NewPoint(10, 10), hexes.NewPoint(0, 0))
+	region := hexes.NewRangeRegion(hexes.NewHex(0, 0, 0), 4)
+	for _, tc := range []struct {
+		id            int
+		octaves, same int
+	}{
+		{1, -1, 4},
+		{2, -100, 4},
+		{3, MaxOctaves + 1, MaxOctaves},
+		{4, 64, MaxOctaves},
+		{5, 1000, MaxOctaves},
+	} {
+		want := checksum(Generate(layout, region, Options{Seed: 3, Octaves: tc.same}))
+		if got := checksum(Generate(layout, region, Options{Seed: 3, Octaves: tc.octaves})); got != want {
+			t.Errorf("%d: octaves %d: want the world for %d octaves", tc.id, tc.octaves, tc.same)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// text encoding
+//
+// Hexes are written in cube notation, "(q, r, s)".
+// The parsers also accept axial notation, "(q, r)", and offset notation, "(column, row)".
+// The parentheses and spaces are optional, so "1,-2,1" and "( 1 , -2 , 1 )" are the same hex.
+// Negative numbers have a leading "-", but positive numbers can't have a leading "+".
+
+var (
+	// ErrSyntax is returned when the text is not a list of integers.
+	ErrSyntax = errors.New("invalid syntax")
+	// ErrComponents is returned when the text has the wrong number of coordinates.
+	ErrComponents = errors.New("wrong number of coordinates")
+	// ErrNotOnPlane is returned when the cube coordinates do not sum to zero.
+	ErrNotOnPlane = errors.New("q + r + s != 0")
+)
+
+// ParseError records a failed conversion.
+type ParseError struct {
+	Func  string // the function that failed (ParseHex, ParseAxial, ...)
+	Input string // the input text
+	Err   error  // the reason the conversion failed
+}
+
+func (e *ParseError) Error() string {
+	return "hexes." + e.Func + ": parsing " + strconv.Quote(e.Input) + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (h Hex) String() string {
+	return fmt.Sprintf("(%d, %d, %d)", h.q, h.r, h.s)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (h Hex) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts cube and axial notation.
+func (h *Hex) UnmarshalText(text []byte) error {
+	hex, err := ParseHex(string(text))
+	if err != nil {
+		return err
+	}
+	*h = hex
+	return nil
+}
+
+// ParseHex parses a hex in either cube or axial notation.
+func ParseHex(s string) (Hex, error) {
+	values, err := parse_coordinates(s)
+	if err != nil {
+		return Hex{}, &ParseError{Func: "ParseHex", Input: s, Err: err}
+	}
+	switch len(values) {
+	case 2:
+		return Hex{q: values[0], r: values[1], s: -values[0] - values[1]}, nil
+	case 3:
+		h := Hex{q: values[0], r: values[1], s: values[2]}
+		if h.q+h.r+h.s != 0 {
+			return Hex{}, &ParseError{Func: "ParseHex", Input: s, Err: ErrNotOnPlane}
+		}
+		return h, nil
+	}
+	return Hex{}, &ParseError{Func: "ParseHex", Input: s, Err: ErrComponents}
+}
+
+// ParseCube parses a hex in cube notation.
+func ParseCube(s string) (Hex, error) {
+	values, err := parse_coordinates(s)
+	if err != nil {
+		return Hex{}, &ParseError{Func: "ParseCube", Input: s, Err: err}
+	} else if len(values) != 3 {
+		return Hex{}, &ParseError{Func: "ParseCube", Input: s, Err: ErrComponents}
+	}
+	h := Hex{q: values[0], r: values[1], s: values[2]}
+	if h.q+h.r+h.s != 0 {
+		return Hex{}, &ParseError{Func: "ParseCube", Input: s, Err: ErrNotOnPlane}
+	}
+	return h, nil
+}
+
+// ParseAxial parses a hex in axial notation.
+func ParseAxial(s string) (Hex, error) {
+	values, err := parse_coordinates(s)
+	if err != nil {
+		return Hex{}, &ParseError{Func: "ParseAxial", Input: s, Err: err}
+	} else if len(values) != 2 {
+		return Hex{}, &ParseError{Func: "ParseAxial", Input: s, Err: ErrComponents}
+	}
+	return Hex{q: values[0], r: values[1], s: -values[0] - values[1]}, nil
+}
+
+// ParseOffset parses a hex in the offset notation of the layout.
+func ParseOffset(layout Layout, s string) (Hex, error) {
+	values, err := parse_coordinates(s)
+	if err != nil {
+		return Hex{}, &ParseError{Func: "ParseOffset", Input: s, Err: err}
+	} else if len(values) != 2 {
+		return Hex{}, &ParseError{Func: "ParseOffset", Input: s, Err: ErrComponents}
+	}
+	return layout.OffsetToHex(values[0], values[1]), nil
+}
+
+// parse_coordinates returns the comma separated integers in the text.
+// The list may be wrapped in parentheses.
+func parse_coordinates(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "(") != strings.HasSuffix(s, ")") {
+		return nil, ErrSyntax
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if strings.HasPrefix(field, "+") {
+			return nil, ErrSyntax
+		}
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return nil, strconv.ErrRange
+			}
+			return nil, ErrSyntax
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
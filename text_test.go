@@ -0,0 +1,130 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestParseHex(t *testing.T) {
+	for _, tc := range []struct {
+		id    int
+		input string
+		want  Hex
+		err   error
+	}{
+		{1, "(1, -2, 1)", NewHex(1, -2, 1), nil},
+		{2, "1,-2,1", NewHex(1, -2, 1), nil},
+		{3, "  ( 1 , -2 , 1 )  ", NewHex(1, -2, 1), nil},
+		{4, "(3, -7)", NewHex(3, -7, 4), nil},
+		{5, "0,0", NewHex(0, 0, 0), nil},
+		{6, "(1, 1, 1)", Hex{}, ErrNotOnPlane},
+		{7, "(1)", Hex{}, ErrComponents},
+		{8, "(1, 2, -3, 0)", Hex{}, ErrComponents},
+		{9, "(1, -2, 1", Hex{}, ErrSyntax},
+		{10, "1, -2, 1)", Hex{}, ErrSyntax},
+		{11, "(a, b)", Hex{}, ErrSyntax},
+		{12, "", Hex{}, ErrSyntax},
+		{13, "(1, , -1)", Hex{}, ErrSyntax},
+		{14, "+1,-1", Hex{}, ErrSyntax},
+		{15, "(1, +1, -2)", Hex{}, ErrSyntax},
+		{16, "(99999999999999999999, 0)", Hex{}, strconv.ErrRange},
+	} {
+		got, err := ParseHex(tc.input)
+		if tc.err == nil {
+			if err != nil || got != tc.want {
+				t.Errorf("%d: ParseHex(%q): want %v: got %v, %v", tc.id, tc.input, tc.want, got, err)
+			}
+			continue
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Func != "ParseHex" || pe.Input != tc.input || !errors.Is(err, tc.err) {
+			t.Errorf("%d: ParseHex(%q): want *ParseError wrapping %v: got %v", tc.id, tc.input, tc.err, err)
+		}
+	}
+}
+
+func TestParseCube(t *testing.T) {
+	for _, tc := range []struct {
+		id    int
+		input string
+		want  Hex
+		err   error
+	}{
+		{1, "(1, -2, 1)", NewHex(1, -2, 1), nil},
+		{2, " -3 ,7,-4 ", NewHex(-3, 7, -4), nil},
+		{3, "(1, -2)", Hex{}, ErrComponents},
+		{4, "(1, 2, 3)", Hex{}, ErrNotOnPlane},
+		{5, "(1; -2; 1)", Hex{}, ErrSyntax},
+	} {
+		got, err := ParseCube(tc.input)
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("%d: ParseCube(%q): want %v, %v: got %v, %v", tc.id, tc.input, tc.want, tc.err, got, err)
+		}
+	}
+}
+
+func TestParseAxial(t *testing.T) {
+	for _, tc := range []struct {
+		id    int
+		input string
+		want  Hex
+		err   error
+	}{
+		{1, "(1, -2)", NewHex(1, -2, 1), nil},
+		{2, "-5,0", NewHex(-5, 0, 5), nil},
+		{3, "(1, -2, 1)", Hex{}, ErrComponents},
+		{4, "(1, 2", Hex{}, ErrSyntax},
+	} {
+		got, err := ParseAxial(tc.input)
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("%d: ParseAxial(%q): want %v, %v: got %v, %v", tc.id, tc.input, tc.want, tc.err, got, err)
+		}
+	}
+}
+
+func TestParseOffset(t *testing.T) {
+	for _, tc := range []struct {
+		id          int
+		layout      Layout
+		input       string
+		column, row int
+		err         error
+	}{
+		{1, NewFlatEvenLayout(NewPoint(1, 1), NewPoint(0, 0)), "(1, 3)", 1, 3, nil},
+		{2, NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0)), "1,2", 1, 2, nil},
+		{3, NewPointyEvenLayout(NewPoint(1, 1), NewPoint(0, 0)), " ( -2 , 5 ) ", -2, 5, nil},
+		{4, NewPointyOddLayout(NewPoint(1, 1), NewPoint(0, 0)), "(4, -1)", 4, -1, nil},
+		{5, NewPointyOddLayout(NewPoint(1, 1), NewPoint(0, 0)), "(4, -1, -3)", 0, 0, ErrComponents},
+	} {
+		got, err := ParseOffset(tc.layout, tc.input)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%d: ParseOffset(%q): want %v: got %v", tc.id, tc.input, tc.err, err)
+			}
+			continue
+		}
+		if want := tc.layout.OffsetToHex(tc.column, tc.row); err != nil || got != want {
+			t.Errorf("%d: ParseOffset(%q): want %v: got %v, %v", tc.id, tc.input, want, got, err)
+		}
+	}
+}
+
+func TestHexText(t *testing.T) {
+	for _, h := range NewHexagonGrid[bool](3).Hexes() {
+		text, err := h.MarshalText()
+		if err != nil {
+			t.Fatalf("%v: MarshalText: %v", h, err)
+		}
+		var got Hex
+		if err := got.UnmarshalText(text); err != nil || got != h {
+			t.Errorf("%v: UnmarshalText(%q): got %v, %v", h, text, got, err)
+		}
+	}
+	got := NewHex(1, -1, 0)
+	if err := got.UnmarshalText([]byte("(1, 1, 1)")); !errors.Is(err, ErrNotOnPlane) || got != NewHex(1, -1, 0) {
+		t.Errorf("UnmarshalText: want %v and the hex unchanged: got %v, %v", ErrNotOnPlane, got, err)
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+// Package tiled imports and exports hexagonal maps made with the Tiled map editor,
+// https://www.mapeditor.org/, in both the XML (.tmx) and JSON (.tmj) formats.
+//
+// Tiled stores a hex map as a rectangle of tiles in offset coordinates.
+// The stagger axis picks the orientation (x is flat, y is pointy) and the
+// stagger index picks the parity (odd or even), so every Tiled hex map
+// has a matching Layout. Tile layers are loaded into grids keyed by cube
+// coordinates and object layers keep the pixel positions of their objects.
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrOrientation = errors.New("not a hexagonal map")
+	ErrInfinite    = errors.New("infinite maps are not supported")
+	ErrEncoding    = errors.New("unsupported encoding")
+	ErrFormat      = errors.New("invalid format")
+)
+
+// FlagMask selects the flip and rotation flags stored in the high bits of a GID.
+// The rest of the bits are the global tile id.
+const FlagMask uint32 = 0xf0000000
+
+// Map is a Tiled hex map.
+type Map struct {
+	// Layout converts between hexes and the pixel coordinates that Tiled uses.
+	// The center of the hex at offset (0, 0) is half a tile from the top left
+	// of the map, plus half a row or column if the first row or column is staggered.
+	Layout hexes.Layout
+
+	Width, Height         int // size of the map in tiles
+	TileWidth, TileHeight int // size of a tile in pixels
+	HexSideLength         int // length of the flat side of a hex in pixels
+
+	Tilesets []Tileset
+	Layers   []*Layer
+}
+
+// Tileset is a reference to the tiles used by the map.
+// If Source is set, the tileset is stored in an external file and the
+// other fields (except FirstGID) are not used.
+type Tileset struct {
+	FirstGID                uint32
+	Source                  string
+	Name                    string
+	TileWidth, TileHeight   int
+	TileCount, Columns      int
+	Image                   string
+	ImageWidth, ImageHeight int
+}
+
+// Layer is a tile layer or an object layer.
+type Layer struct {
+	ID   int
+	Name string
+
+	// Tiles holds the GID of each tile in a tile layer. It is bounded
+	// by the rectangle of the map. Empty tiles are not set. Tiles is
+	// nil for object layers.
+	Tiles *hexes.Grid[uint32]
+
+	// Objects holds the objects in an object layer.
+	Objects []Object
+}
+
+// Object is an object from an object layer.
+type Object struct {
+	ID            int
+	Name, Type    string
+	GID           uint32      // zero unless the object is a tile
+	Point         hexes.Point // position in pixels
+	Width, Height float64
+}
+
+// Center returns the center of the object in pixels.
+// Tiled anchors tile objects at their bottom left corner and
+// every other object at its top left corner.
+func (o Object) Center() hexes.Point {
+	if o.GID != 0 {
+		return hexes.NewPoint(o.Point.X+o.Width/2, o.Point.Y-o.Height/2)
+	}
+	return hexes.NewPoint(o.Point.X+o.Width/2, o.Point.Y+o.Height/2)
+}
+
+// Hex returns the hex that contains the center of the object.
+func (o Object) Hex(layout hexes.Layout) hexes.Hex {
+	return layout.PixelToHex(o.Center())
+}
+
+// NewMap returns an empty map with the orientation and parity of the layout.
+// The tile size is the size of a regular hex with the layout's hex size,
+// rounded to whole pixels. The map's Layout is built from the rounded tile
+// size, so it matches the way Tiled will draw the map.
+func NewMap(layout hexes.Layout, width, height int) *Map {
+	size := layout.Size()
+	m := &Map{Width: width, Height: height}
+	if layout.Orientation().IsFlat() {
+		m.TileWidth = int(math.Round(2 * size.X))
+		m.TileHeight = int(math.Round(math.Sqrt(3) * size.Y))
+		m.HexSideLength = int(math.Round(size.X))
+	} else {
+		m.TileWidth = int(math.Round(math.Sqrt(3) * size.X))
+		m.TileHeight = int(math.Round(2 * size.Y))
+		m.HexSideLength = int(math.Round(size.Y))
+	}
+	m.Layout = tiled_layout(layout.Orientation(), layout.Parity(), m.TileWidth, m.TileHeight, m.HexSideLength)
+	return m
+}
+
+// AddTileLayer adds an empty tile layer to the map.
+func (m *Map) AddTileLayer(name string) *Layer {
+	layer := &Layer{
+		ID:    m.next_layer_id(),
+		Name:  name,
+		Tiles: hexes.NewRectangleGrid[uint32](m.Layout, m.Width, m.Height),
+	}
+	m.Layers = append(m.Layers, layer)
+	return layer
+}
+
+// AddObjectLayer adds an empty object layer to the map.
+func (m *Map) AddObjectLayer(name string) *Layer {
+	layer := &Layer{ID: m.next_layer_id(), Name: name}
+	m.Layers = append(m.Layers, layer)
+	return layer
+}
+
+func (m *Map) next_layer_id() int {
+	id := 0
+	for _, layer := range m.Layers {
+		id = max(id, layer.ID)
+	}
+	return id + 1
+}
+
+func (m *Map) next_object_id() int {
+	id := 0
+	for _, layer := range m.Layers {
+		for _, o := range layer.Objects {
+			id = max(id, o.ID)
+		}
+	}
+	return id + 1
+}
+
+// stagger returns the Tiled stagger axis and index for the map's layout.
+func (m *Map) stagger() (axis, index string) {
+	axis, index = "y", "odd"
+	if m.Layout.Orientation().IsFlat() {
+		axis = "x"
+	}
+	if m.Layout.Parity() == hexes.Even {
+		index = "even"
+	}
+	return axis, index
+}
+
+// new_map returns an empty map for the settings read from a Tiled file.
+func new_map(orientation, staggerAxis, staggerIndex string, infinite bool, width, height, tileWidth, tileHeight, hexSideLength int) (*Map, error) {
+	if orientation != "hexagonal" {
+		return nil, fmt.Errorf("%q: %w", orientation, ErrOrientation)
+	} else if infinite {
+		return nil, ErrInfinite
+	}
+
+	var o hexes.Orientation
+	switch staggerAxis {
+	case "x":
+		o = hexes.Flat()
+	case "y":
+		o = hexes.Pointy()
+	default:
+		return nil, fmt.Errorf("staggeraxis %q: %w", staggerAxis, ErrFormat)
+	}
+	var parity hexes.Parity
+	switch staggerIndex {
+	case "even":
+		parity = hexes.Even
+	case "odd":
+		parity = hexes.Odd
+	default:
+		return nil, fmt.Errorf("staggerindex %q: %w", staggerIndex, ErrFormat)
+	}
+
+	return &Map{
+		Layout:        tiled_layout(o, parity, tileWidth, tileHeight, hexSideLength),
+		Width:         width,
+		Height:        height,
+		TileWidth:     tileWidth,
+		TileHeight:    tileHeight,
+		HexSideLength: hexSideLength,
+	}, nil
+}
+
+// tiled_layout returns the layout that puts hex centers where Tiled draws them.
+//
+// For flat hexes, Tiled steps (width + side) / 2 pixels from one column to the
+// next and height pixels from one row to the next. A layout steps 3/2 * size.X
+// and sqrt(3) * size.Y. Pointy hexes are the same with the axes swapped.
+func tiled_layout(orientation hexes.Orientation, parity hexes.Parity, width, height, side int) hexes.Layout {
+	w, h, l := float64(width), float64(height), float64(side)
+	var size, origin hexes.Point
+	if orientation.IsFlat() {
+		size = hexes.NewPoint((w+l)/3, h/math.Sqrt(3))
+		origin = hexes.NewPoint(w/2, h/2)
+		if parity == hexes.Even {
+			origin.Y = h
+		}
+	} else {
+		size = hexes.NewPoint(w/math.Sqrt(3), (h+l)/3)
+		origin = hexes.NewPoint(w/2, h/2)
+		if parity == hexes.Even {
+			origin.X = w
+		}
+	}
+	return hexes.NewLayout(orientation, parity, size, origin)
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// tile data
+//
+// Tiled stores the tiles of a layer in row order, one GID per tile.
+
+// load_tiles returns a tile layer holding the GIDs.
+func (m *Map) load_tiles(id int, name string, gids []uint32) (*Layer, error) {
+	if len(gids) != m.Width*m.Height {
+		return nil, fmt.Errorf("layer %q: %d tiles for a %dx%d map: %w", name, len(gids), m.Width, m.Height, ErrFormat)
+	}
+	layer := &Layer{ID: id, Name: name, Tiles: hexes.NewRectangleGrid[uint32](m.Layout, m.Width, m.Height)}
+	for i, gid := range gids {
+		if gid != 0 {
+			layer.Tiles.Set(m.Layout.OffsetToHex(i%m.Width, i/m.Width), gid)
+		}
+	}
+	return layer, nil
+}
+
+// gids returns the GIDs of the tile layer in row order.
+func (m *Map) gids(layer *Layer) []uint32 {
+	gids := make([]uint32, 0, m.Width*m.Height)
+	for row := 0; row < m.Height; row++ {
+		for column := 0; column < m.Width; column++ {
+			gid, _ := layer.Tiles.Get(m.Layout.OffsetToHex(column, row))
+			gids = append(gids, gid)
+		}
+	}
+	return gids
+}
+
+// decode_csv decodes tile data stored as comma separated values.
+func decode_csv(text string) ([]uint32, error) {
+	var gids []uint32
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		gid, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", field, ErrFormat)
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids, nil
+}
+
+// encode_csv encodes tile data as comma separated values, one row per line.
+func encode_csv(gids []uint32, width int) string {
+	var sb strings.Builder
+	for i, gid := range gids {
+		if i != 0 {
+			sb.WriteByte(',')
+			if i%width == 0 {
+				sb.WriteByte('\n')
+			}
+		}
+		sb.WriteString(strconv.FormatUint(uint64(gid), 10))
+	}
+	return sb.String()
+}
+
+// decode_base64 decodes tile data stored as little-endian GIDs,
+// optionally compressed, and then base64 encoded.
+func decode_base64(text, compression string) ([]uint32, error) {
+	buf, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, fmt.Errorf("base64: %w", ErrFormat)
+	}
+
+	var r io.Reader = bytes.NewReader(buf)
+	switch compression {
+	case "":
+	case "zlib":
+		if r, err = zlib.NewReader(r); err != nil {
+			return nil, fmt.Errorf("zlib: %w", err)
+		}
+	case "gzip":
+		if r, err = gzip.NewReader(r); err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("compression %q: %w", compression, ErrEncoding)
+	}
+	if buf, err = io.ReadAll(r); err != nil {
+		return nil, fmt.Errorf("%s: %w", compression, err)
+	} else if len(buf)%4 != 0 {
+		return nil, fmt.Errorf("base64: %d bytes: %w", len(buf), ErrFormat)
+	}
+
+	gids := make([]uint32, len(buf)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(buf[4*i:])
+	}
+	return gids, nil
+}
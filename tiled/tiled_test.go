@@ -0,0 +1,171 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package tiled
+
+import (
+	"bytes"
+	"errors"
+	"github.com/playbymail/hexes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestObjectHex(t *testing.T) {
+	m := NewMap(hexes.NewFlatOddLayout(hexes.NewPoint(20, 20), hexes.NewPoint(0, 0)), 10, 10)
+	h := m.Layout.OffsetToHex(3, 4)
+	center := m.Layout.HexToCenterPoint(h)
+	w, ht := float64(m.TileWidth), float64(m.TileHeight)
+	for _, tc := range []struct {
+		id     int
+		object Object
+	}{
+		// tile objects are anchored at the bottom left.
+		{1, Object{GID: 1, Point: hexes.NewPoint(center.X-w/2, center.Y+ht/2), Width: w, Height: ht}},
+		// rectangles are anchored at the top left.
+		{2, Object{Point: hexes.NewPoint(center.X-w/2, center.Y-ht/2), Width: w, Height: ht}},
+		// points have no size.
+		{3, Object{Point: center}},
+	} {
+		if got := tc.object.Center(); got.Distance(center) > 1e-9 {
+			t.Errorf("%d: Center: want %v: got %v", tc.id, center, got)
+		}
+		if got := tc.object.Hex(m.Layout); got != h {
+			t.Errorf("%d: Hex: want %v: got %v", tc.id, h, got)
+		}
+	}
+}
+
+// fixtures are maps saved in every stagger axis and index, with the tile
+// data in every encoding. Each holds the same tiles and objects.
+var fixtures = []struct {
+	file string
+	read func(r io.Reader) (*Map, error)
+	flat bool
+	odd  bool
+}{
+	{"flat-even-xml.tmx", ReadTMX, true, false},
+	{"flat-odd-csv.tmx", ReadTMX, true, true},
+	{"pointy-even-base64.tmx", ReadTMX, false, false},
+	{"pointy-odd-zlib.tmx", ReadTMX, false, true},
+	{"flat-even-gzip.tmx", ReadTMX, true, false},
+	{"flat-even-base64.tmj", ReadTMJ, true, false},
+	{"flat-odd-array.tmj", ReadTMJ, true, true},
+	{"pointy-even-gzip.tmj", ReadTMJ, false, false},
+	{"pointy-odd-zlib.tmj", ReadTMJ, false, true},
+}
+
+// fixture_gids are the tiles of the fixtures in row order. The last one is flipped.
+var fixture_gids = []uint32{1, 0, 2, 3, 4, 0x80000005}
+
+func read_fixture(t *testing.T, file string, read func(r io.Reader) (*Map, error)) *Map {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, err := read(f)
+	if err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	return m
+}
+
+// check_fixture checks that the map holds the tiles and objects of the fixtures.
+func check_fixture(t *testing.T, name string, m *Map, flat, odd bool) {
+	t.Helper()
+	if m.Width != 3 || m.Height != 2 || m.HexSideLength != 14 {
+		t.Errorf("%s: size: want 3 x 2 with side 14: got %d x %d with side %d", name, m.Width, m.Height, m.HexSideLength)
+	}
+	if m.Layout.Orientation().IsFlat() != flat || (m.Layout.Parity() == hexes.Odd) != odd {
+		t.Errorf("%s: layout: want flat %v, odd %v: got %v, %v", name, flat, odd, m.Layout.Orientation().IsFlat(), m.Layout.Parity())
+	}
+	if len(m.Tilesets) != 2 || m.Tilesets[0].Source != "terrain.tsx" || m.Tilesets[1].Image != "units.png" || m.Tilesets[1].FirstGID != 9 {
+		t.Errorf("%s: tilesets: got %+v", name, m.Tilesets)
+	}
+	if len(m.Layers) != 2 {
+		t.Fatalf("%s: layers: want 2: got %d", name, len(m.Layers))
+	}
+	if got := m.gids(m.Layers[0]); !reflect.DeepEqual(got, fixture_gids) {
+		t.Errorf("%s: tiles: want %v: got %v", name, fixture_gids, got)
+	}
+	if gid, _ := m.Layers[0].Tiles.Get(m.Layout.OffsetToHex(2, 0)); gid != 2 {
+		t.Errorf("%s: tile (2, 0): want 2: got %d", name, gid)
+	}
+	want := []Object{
+		{ID: 1, Name: "scout", Type: "unit", GID: 9, Point: hexes.NewPoint(10, 40), Width: float64(m.TileWidth), Height: float64(m.TileHeight)},
+		{ID: 2, Name: "Tarn", Type: "town", Point: hexes.NewPoint(30.5, 4.25), Width: 12, Height: 8},
+	}
+	if m.Layers[1].Tiles != nil || !reflect.DeepEqual(m.Layers[1].Objects, want) {
+		t.Errorf("%s: objects: want %+v: got %+v", name, want, m.Layers[1].Objects)
+	}
+}
+
+// TestRoundTrip reads each fixture, writes it in both formats and reads it back.
+func TestRoundTrip(t *testing.T) {
+	formats := []struct {
+		name  string
+		read  func(r io.Reader) (*Map, error)
+		write func(w io.Writer, m *Map) error
+	}{
+		{"tmx", ReadTMX, WriteTMX},
+		{"tmj", ReadTMJ, WriteTMJ},
+	}
+	for _, fixture := range fixtures {
+		m := read_fixture(t, fixture.file, fixture.read)
+		check_fixture(t, fixture.file, m, fixture.flat, fixture.odd)
+		for _, format := range formats {
+			var buf bytes.Buffer
+			if err := format.write(&buf, m); err != nil {
+				t.Fatalf("%s: %s: write: %v", fixture.file, format.name, err)
+			}
+			got, err := format.read(&buf)
+			if err != nil {
+				t.Fatalf("%s: %s: read: %v", fixture.file, format.name, err)
+			}
+			check_fixture(t, fixture.file+": "+format.name, got, fixture.flat, fixture.odd)
+			if !reflect.DeepEqual(got, m) {
+				t.Errorf("%s: %s: want %+v: got %+v", fixture.file, format.name, m, got)
+			}
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tmx := func(attrs, data string) string {
+		return `<map orientation="hexagonal" width="2" height="2" tilewidth="28" tileheight="24" hexsidelength="14" staggeraxis="x" staggerindex="odd" ` + attrs + `>` +
+			`<layer id="1" name="terrain" width="2" height="2">` + data + `</layer></map>`
+	}
+	tmj := func(attrs, layer string) string {
+		return `{"orientation": "hexagonal", "width": 2, "height": 2, "tilewidth": 28, "tileheight": 24, "hexsidelength": 14, "staggeraxis": "x", "staggerindex": "odd"` + attrs +
+			`, "layers": [{"id": 1, "name": "terrain", "type": "tilelayer", "width": 2, "height": 2` + layer + `}]}`
+	}
+	for _, tc := range []struct {
+		id    int
+		read  func(r io.Reader) (*Map, error)
+		input string
+		want  error
+	}{
+		{1, ReadTMX, strings.Replace(tmx("", `<data encoding="csv">1,2,3,4</data>`), "hexagonal", "orthogonal", 1), ErrOrientation},
+		{2, ReadTMX, tmx(`infinite="1"`, `<data encoding="csv">1,2,3,4</data>`), ErrInfinite},
+		{3, ReadTMX, tmx("", `<data encoding="hex">01020304</data>`), ErrEncoding},
+		{4, ReadTMX, tmx("", `<data encoding="base64" compression="zstd">AQAAAA==</data>`), ErrEncoding},
+		{5, ReadTMX, tmx("", `<data encoding="csv">1,2,3</data>`), ErrFormat},
+		{6, ReadTMX, tmx("", `<data encoding="csv">1,2,x,4</data>`), ErrFormat},
+		{7, ReadTMX, tmx("", `<data encoding="base64">AQAAAAIAAAA=</data>`), ErrFormat},
+		{8, ReadTMX, strings.Replace(tmx("", `<data encoding="csv">1,2,3,4</data>`), `staggeraxis="x"`, `staggeraxis="z"`, 1), ErrFormat},
+		{9, ReadTMJ, strings.Replace(tmj("", `, "data": [1, 2, 3, 4]`), "hexagonal", "isometric", 1), ErrOrientation},
+		{10, ReadTMJ, tmj(`, "infinite": true`, `, "data": [1, 2, 3, 4]`), ErrInfinite},
+		{11, ReadTMJ, tmj("", `, "encoding": "hex", "data": "01020304"`), ErrEncoding},
+		{12, ReadTMJ, tmj("", `, "data": [1, 2, 3, 4, 5]`), ErrFormat},
+		{13, ReadTMJ, tmj("", `, "encoding": "base64", "data": [1, 2, 3, 4]`), ErrFormat},
+	} {
+		if _, err := tc.read(strings.NewReader(tc.input)); !errors.Is(err, tc.want) {
+			t.Errorf("%d: want %v: got %v", tc.id, tc.want, err)
+		}
+	}
+}
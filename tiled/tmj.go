@@ -0,0 +1,217 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+)
+
+// The JSON format is described at https://doc.mapeditor.org/en/stable/reference/json-map-format/.
+// Image layers and group layers are skipped when reading.
+
+type tmj_map struct {
+	Type          string        `json:"type"`
+	Version       string        `json:"version"`
+	Orientation   string        `json:"orientation"`
+	RenderOrder   string        `json:"renderorder"`
+	Width         int           `json:"width"`
+	Height        int           `json:"height"`
+	TileWidth     int           `json:"tilewidth"`
+	TileHeight    int           `json:"tileheight"`
+	HexSideLength int           `json:"hexsidelength"`
+	StaggerAxis   string        `json:"staggeraxis"`
+	StaggerIndex  string        `json:"staggerindex"`
+	Infinite      bool          `json:"infinite"`
+	NextLayerID   int           `json:"nextlayerid"`
+	NextObjectID  int           `json:"nextobjectid"`
+	Tilesets      []tmj_tileset `json:"tilesets"`
+	Layers        []tmj_layer   `json:"layers"`
+}
+
+type tmj_tileset struct {
+	FirstGID    uint32 `json:"firstgid"`
+	Source      string `json:"source,omitempty"`
+	Name        string `json:"name,omitempty"`
+	TileWidth   int    `json:"tilewidth,omitempty"`
+	TileHeight  int    `json:"tileheight,omitempty"`
+	TileCount   int    `json:"tilecount,omitempty"`
+	Columns     int    `json:"columns,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ImageWidth  int    `json:"imagewidth,omitempty"`
+	ImageHeight int    `json:"imageheight,omitempty"`
+}
+
+type tmj_layer struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"` // "tilelayer" or "objectgroup"
+	Width       int             `json:"width,omitempty"`
+	Height      int             `json:"height,omitempty"`
+	X           int             `json:"x"`
+	Y           int             `json:"y"`
+	Opacity     float64         `json:"opacity"`
+	Visible     bool            `json:"visible"`
+	Encoding    string          `json:"encoding,omitempty"`
+	Compression string          `json:"compression,omitempty"`
+	Data        json.RawMessage `json:"data,omitempty"` // array of GIDs, or a string if base64 encoded
+	DrawOrder   string          `json:"draworder,omitempty"`
+	Objects     []tmj_object    `json:"objects,omitempty"`
+}
+
+type tmj_object struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Class    string  `json:"class,omitempty"` // Tiled 1.9 renamed type to class
+	GID      uint32  `json:"gid,omitempty"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Rotation float64 `json:"rotation"`
+	Visible  bool    `json:"visible"`
+}
+
+// ReadTMJ reads a map in the Tiled JSON format.
+func ReadTMJ(r io.Reader) (*Map, error) {
+	var tm tmj_map
+	if err := json.NewDecoder(r).Decode(&tm); err != nil {
+		return nil, err
+	}
+	m, err := new_map(tm.Orientation, tm.StaggerAxis, tm.StaggerIndex, tm.Infinite, tm.Width, tm.Height, tm.TileWidth, tm.TileHeight, tm.HexSideLength)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ts := range tm.Tilesets {
+		m.Tilesets = append(m.Tilesets, Tileset{
+			FirstGID:    ts.FirstGID,
+			Source:      ts.Source,
+			Name:        ts.Name,
+			TileWidth:   ts.TileWidth,
+			TileHeight:  ts.TileHeight,
+			TileCount:   ts.TileCount,
+			Columns:     ts.Columns,
+			Image:       ts.Image,
+			ImageWidth:  ts.ImageWidth,
+			ImageHeight: ts.ImageHeight,
+		})
+	}
+
+	for _, tl := range tm.Layers {
+		switch tl.Type {
+		case "tilelayer":
+			gids, err := tl.decode()
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+			}
+			layer, err := m.load_tiles(tl.ID, tl.Name, gids)
+			if err != nil {
+				return nil, err
+			}
+			m.Layers = append(m.Layers, layer)
+		case "objectgroup":
+			layer := &Layer{ID: tl.ID, Name: tl.Name}
+			for _, o := range tl.Objects {
+				if o.Type == "" {
+					o.Type = o.Class
+				}
+				layer.Objects = append(layer.Objects, Object{
+					ID:     o.ID,
+					Name:   o.Name,
+					Type:   o.Type,
+					GID:    o.GID,
+					Point:  hexes.NewPoint(o.X, o.Y),
+					Width:  o.Width,
+					Height: o.Height,
+				})
+			}
+			m.Layers = append(m.Layers, layer)
+		}
+	}
+
+	return m, nil
+}
+
+func (tl *tmj_layer) decode() ([]uint32, error) {
+	switch tl.Encoding {
+	case "", "csv":
+		var gids []uint32
+		if err := json.Unmarshal(tl.Data, &gids); err != nil {
+			return nil, fmt.Errorf("data: %w", ErrFormat)
+		}
+		return gids, nil
+	case "base64":
+		var text string
+		if err := json.Unmarshal(tl.Data, &text); err != nil {
+			return nil, fmt.Errorf("data: %w", ErrFormat)
+		}
+		return decode_base64(text, tl.Compression)
+	}
+	return nil, fmt.Errorf("encoding %q: %w", tl.Encoding, ErrEncoding)
+}
+
+// WriteTMJ writes the map in the Tiled JSON format. Tile data is written as an array of GIDs.
+func WriteTMJ(w io.Writer, m *Map) error {
+	axis, index := m.stagger()
+	tm := tmj_map{
+		Type:          "map",
+		Version:       "1.10",
+		Orientation:   "hexagonal",
+		RenderOrder:   "right-down",
+		Width:         m.Width,
+		Height:        m.Height,
+		TileWidth:     m.TileWidth,
+		TileHeight:    m.TileHeight,
+		HexSideLength: m.HexSideLength,
+		StaggerAxis:   axis,
+		StaggerIndex:  index,
+		NextLayerID:   m.next_layer_id(),
+		NextObjectID:  m.next_object_id(),
+		Tilesets:      []tmj_tileset{},
+		Layers:        []tmj_layer{},
+	}
+
+	for _, tileset := range m.Tilesets {
+		ts := tmj_tileset{FirstGID: tileset.FirstGID, Source: tileset.Source}
+		if tileset.Source == "" {
+			ts.Name = tileset.Name
+			ts.TileWidth, ts.TileHeight = tileset.TileWidth, tileset.TileHeight
+			ts.TileCount, ts.Columns = tileset.TileCount, tileset.Columns
+			ts.Image, ts.ImageWidth, ts.ImageHeight = tileset.Image, tileset.ImageWidth, tileset.ImageHeight
+		}
+		tm.Tilesets = append(tm.Tilesets, ts)
+	}
+
+	for _, layer := range m.Layers {
+		tl := tmj_layer{ID: layer.ID, Name: layer.Name, Opacity: 1, Visible: true}
+		if layer.Tiles != nil {
+			data, err := json.Marshal(m.gids(layer))
+			if err != nil {
+				return err
+			}
+			tl.Type, tl.Width, tl.Height, tl.Data = "tilelayer", m.Width, m.Height, data
+		} else {
+			tl.Type, tl.DrawOrder = "objectgroup", "topdown"
+			for _, o := range layer.Objects {
+				tl.Objects = append(tl.Objects, tmj_object{
+					ID:      o.ID,
+					Name:    o.Name,
+					Type:    o.Type,
+					GID:     o.GID,
+					X:       o.Point.X,
+					Y:       o.Point.Y,
+					Width:   o.Width,
+					Height:  o.Height,
+					Visible: true,
+				})
+			}
+		}
+		tm.Layers = append(tm.Layers, tl)
+	}
+
+	return json.NewEncoder(w).Encode(tm)
+}
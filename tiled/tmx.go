@@ -0,0 +1,233 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package tiled
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/playbymail/hexes"
+	"io"
+)
+
+// The XML format is described at https://doc.mapeditor.org/en/stable/reference/tmx-map-format/.
+// Image layers and group layers are skipped when reading.
+
+type tmx_map struct {
+	XMLName       xml.Name      `xml:"map"`
+	Version       string        `xml:"version,attr"`
+	Orientation   string        `xml:"orientation,attr"`
+	RenderOrder   string        `xml:"renderorder,attr"`
+	Width         int           `xml:"width,attr"`
+	Height        int           `xml:"height,attr"`
+	TileWidth     int           `xml:"tilewidth,attr"`
+	TileHeight    int           `xml:"tileheight,attr"`
+	HexSideLength int           `xml:"hexsidelength,attr"`
+	StaggerAxis   string        `xml:"staggeraxis,attr"`
+	StaggerIndex  string        `xml:"staggerindex,attr"`
+	Infinite      int           `xml:"infinite,attr"`
+	NextLayerID   int           `xml:"nextlayerid,attr"`
+	NextObjectID  int           `xml:"nextobjectid,attr"`
+	Tilesets      []tmx_tileset `xml:"tileset"`
+	Layers        []tmx_layer   `xml:",any"` // tile and object layers, in drawing order
+}
+
+type tmx_tileset struct {
+	FirstGID   uint32     `xml:"firstgid,attr"`
+	Source     string     `xml:"source,attr,omitempty"`
+	Name       string     `xml:"name,attr,omitempty"`
+	TileWidth  int        `xml:"tilewidth,attr,omitempty"`
+	TileHeight int        `xml:"tileheight,attr,omitempty"`
+	TileCount  int        `xml:"tilecount,attr,omitempty"`
+	Columns    int        `xml:"columns,attr,omitempty"`
+	Image      *tmx_image `xml:"image"`
+}
+
+type tmx_image struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr,omitempty"`
+	Height int    `xml:"height,attr,omitempty"`
+}
+
+type tmx_layer struct {
+	XMLName xml.Name     // "layer" or "objectgroup"
+	ID      int          `xml:"id,attr"`
+	Name    string       `xml:"name,attr"`
+	Width   int          `xml:"width,attr,omitempty"`
+	Height  int          `xml:"height,attr,omitempty"`
+	Data    *tmx_data    `xml:"data"`
+	Objects []tmx_object `xml:"object"`
+}
+
+type tmx_data struct {
+	Encoding    string     `xml:"encoding,attr,omitempty"`
+	Compression string     `xml:"compression,attr,omitempty"`
+	Text        string     `xml:",innerxml"` // csv and base64 data never need escaping
+	Tiles       []tmx_tile `xml:"tile"`
+}
+
+type tmx_tile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tmx_object struct {
+	ID     int     `xml:"id,attr"`
+	Name   string  `xml:"name,attr,omitempty"`
+	Type   string  `xml:"type,attr,omitempty"`
+	Class  string  `xml:"class,attr,omitempty"` // Tiled 1.9 renamed type to class
+	GID    uint32  `xml:"gid,attr,omitempty"`
+	X      float64 `xml:"x,attr"`
+	Y      float64 `xml:"y,attr"`
+	Width  float64 `xml:"width,attr,omitempty"`
+	Height float64 `xml:"height,attr,omitempty"`
+}
+
+// ReadTMX reads a map in the Tiled XML format.
+func ReadTMX(r io.Reader) (*Map, error) {
+	var tm tmx_map
+	if err := xml.NewDecoder(r).Decode(&tm); err != nil {
+		return nil, err
+	}
+	m, err := new_map(tm.Orientation, tm.StaggerAxis, tm.StaggerIndex, tm.Infinite != 0, tm.Width, tm.Height, tm.TileWidth, tm.TileHeight, tm.HexSideLength)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ts := range tm.Tilesets {
+		tileset := Tileset{
+			FirstGID:   ts.FirstGID,
+			Source:     ts.Source,
+			Name:       ts.Name,
+			TileWidth:  ts.TileWidth,
+			TileHeight: ts.TileHeight,
+			TileCount:  ts.TileCount,
+			Columns:    ts.Columns,
+		}
+		if ts.Image != nil {
+			tileset.Image, tileset.ImageWidth, tileset.ImageHeight = ts.Image.Source, ts.Image.Width, ts.Image.Height
+		}
+		m.Tilesets = append(m.Tilesets, tileset)
+	}
+
+	for _, tl := range tm.Layers {
+		switch tl.XMLName.Local {
+		case "layer":
+			gids, err := tl.Data.decode()
+			if err != nil {
+				return nil, fmt.Errorf("layer %q: %w", tl.Name, err)
+			}
+			layer, err := m.load_tiles(tl.ID, tl.Name, gids)
+			if err != nil {
+				return nil, err
+			}
+			m.Layers = append(m.Layers, layer)
+		case "objectgroup":
+			layer := &Layer{ID: tl.ID, Name: tl.Name}
+			for _, o := range tl.Objects {
+				if o.Type == "" {
+					o.Type = o.Class
+				}
+				layer.Objects = append(layer.Objects, Object{
+					ID:     o.ID,
+					Name:   o.Name,
+					Type:   o.Type,
+					GID:    o.GID,
+					Point:  hexes.NewPoint(o.X, o.Y),
+					Width:  o.Width,
+					Height: o.Height,
+				})
+			}
+			m.Layers = append(m.Layers, layer)
+		}
+	}
+
+	return m, nil
+}
+
+func (d *tmx_data) decode() ([]uint32, error) {
+	if d == nil {
+		return nil, fmt.Errorf("missing data: %w", ErrFormat)
+	}
+	switch d.Encoding {
+	case "":
+		gids := make([]uint32, len(d.Tiles))
+		for i, tile := range d.Tiles {
+			gids[i] = tile.GID
+		}
+		return gids, nil
+	case "csv":
+		return decode_csv(d.Text)
+	case "base64":
+		return decode_base64(d.Text, d.Compression)
+	}
+	return nil, fmt.Errorf("encoding %q: %w", d.Encoding, ErrEncoding)
+}
+
+// WriteTMX writes the map in the Tiled XML format. Tile data is written as CSV.
+func WriteTMX(w io.Writer, m *Map) error {
+	axis, index := m.stagger()
+	tm := tmx_map{
+		Version:       "1.10",
+		Orientation:   "hexagonal",
+		RenderOrder:   "right-down",
+		Width:         m.Width,
+		Height:        m.Height,
+		TileWidth:     m.TileWidth,
+		TileHeight:    m.TileHeight,
+		HexSideLength: m.HexSideLength,
+		StaggerAxis:   axis,
+		StaggerIndex:  index,
+		NextLayerID:   m.next_layer_id(),
+		NextObjectID:  m.next_object_id(),
+	}
+
+	for _, tileset := range m.Tilesets {
+		ts := tmx_tileset{
+			FirstGID:   tileset.FirstGID,
+			Source:     tileset.Source,
+			Name:       tileset.Name,
+			TileWidth:  tileset.TileWidth,
+			TileHeight: tileset.TileHeight,
+			TileCount:  tileset.TileCount,
+			Columns:    tileset.Columns,
+		}
+		if tileset.Source == "" && tileset.Image != "" {
+			ts.Image = &tmx_image{Source: tileset.Image, Width: tileset.ImageWidth, Height: tileset.ImageHeight}
+		}
+		tm.Tilesets = append(tm.Tilesets, ts)
+	}
+
+	for _, layer := range m.Layers {
+		tl := tmx_layer{ID: layer.ID, Name: layer.Name}
+		if layer.Tiles != nil {
+			tl.XMLName.Local = "layer"
+			tl.Width, tl.Height = m.Width, m.Height
+			tl.Data = &tmx_data{Encoding: "csv", Text: "\n" + encode_csv(m.gids(layer), m.Width) + "\n"}
+		} else {
+			tl.XMLName.Local = "objectgroup"
+			for _, o := range layer.Objects {
+				tl.Objects = append(tl.Objects, tmx_object{
+					ID:     o.ID,
+					Name:   o.Name,
+					Type:   o.Type,
+					GID:    o.GID,
+					X:      o.Point.X,
+					Y:      o.Point.Y,
+					Width:  o.Width,
+					Height: o.Height,
+				})
+			}
+		}
+		tm.Layers = append(tm.Layers, tl)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", " ")
+	if err := enc.Encode(tm); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
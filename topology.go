@@ -0,0 +1,270 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// topology
+//
+// A topology is a rectangle of offset columns and rows that may wrap around.
+// A cylinder wraps east-west, so stepping off the last column lands on the
+// first. A torus also wraps north-south.
+//
+// Wrapping is done in offset coordinates, but the results are in cube
+// coordinates. Moving a hex by a full period must not change which rows or
+// columns are pushed, so the number of columns must be even when a flat
+// layout wraps columns, and the number of rows must be even when a pointy
+// layout wraps rows.
+
+// ErrTopology is returned when the size of a topology doesn't work with its layout.
+var ErrTopology = errors.New("invalid topology size")
+
+// Topology is the shape of the world.
+type Topology interface {
+	Layout() Layout
+	Columns() int
+	Rows() int
+
+	// Hexes returns the hexes in the world, in canonical form.
+	Hexes() []Hex
+
+	// Canonical returns the hex in the world that is the same as h.
+	// It returns false if h is off the edge of a side that doesn't wrap.
+	Canonical(h Hex) (Hex, bool)
+
+	// Neighbor returns the neighbor of the hex in canonical form.
+	// It returns false if the neighbor is off the edge of the world.
+	Neighbor(h Hex, direction Direction) (Hex, bool)
+	Neighbors(h Hex) []Hex
+
+	// Distance returns the length of the shortest path between the hexes,
+	// including paths that cross the seams.
+	Distance(a, b Hex) int
+
+	// LineDraw returns the shortest line between the hexes, in canonical form.
+	LineDraw(a, b Hex) []Hex
+
+	// Bounds returns the screen rectangle covered by one copy of the world.
+	// Hexes on a seam stick out past the rectangle on one side. If the layout
+	// has a camera that rotates the map, this is the bounding box of the
+	// rotated rectangle.
+	Bounds() (topLeft, bottomRight Point)
+
+	// Images returns the copies of the hex that overlap one copy of the world.
+	// The first is the hex in canonical form. Hexes that straddle a seam
+	// have a second copy on the other side, so a renderer that draws every
+	// image of every hex, clipped to Bounds, fills both edges of the map.
+	Images(h Hex) []Hex
+}
+
+type wrapTopology struct {
+	layout        Layout
+	world         Layout // the layout without its camera
+	camera        Affine
+	columns, rows int
+	wrapColumns   bool
+	wrapRows      bool
+	columnPeriod  Hex   // the cube vector that moves a hex one period east
+	rowPeriod     Hex   // the cube vector that moves a hex one period south
+	topLeft       Point // one copy of the world, before the camera
+	bottomRight   Point
+}
+
+// NewBoundedTopology returns a world that doesn't wrap.
+func NewBoundedTopology(layout Layout, columns, rows int) (Topology, error) {
+	return new_topology(layout, columns, rows, false, false)
+}
+
+// NewCylinderTopology returns a world that wraps east-west.
+func NewCylinderTopology(layout Layout, columns, rows int) (Topology, error) {
+	return new_topology(layout, columns, rows, true, false)
+}
+
+// NewTorusTopology returns a world that wraps east-west and north-south.
+func NewTorusTopology(layout Layout, columns, rows int) (Topology, error) {
+	return new_topology(layout, columns, rows, true, true)
+}
+
+func new_topology(layout Layout, columns, rows int, wrapColumns, wrapRows bool) (Topology, error) {
+	if columns < 1 || rows < 1 {
+		return nil, fmt.Errorf("%d x %d: %w", columns, rows, ErrTopology)
+	} else if wrapColumns && layout.Orientation().IsFlat() && columns%2 != 0 {
+		return nil, fmt.Errorf("%d columns: %w", columns, ErrTopology)
+	} else if wrapRows && layout.Orientation().IsPointy() && rows%2 != 0 {
+		return nil, fmt.Errorf("%d rows: %w", rows, ErrTopology)
+	}
+	origin := layout.OffsetToHex(0, 0)
+	world, camera := split_camera(layout)
+	t := &wrapTopology{
+		layout:       layout,
+		world:        world,
+		camera:       camera,
+		columns:      columns,
+		rows:         rows,
+		wrapColumns:  wrapColumns,
+		wrapRows:     wrapRows,
+		columnPeriod: hex_subtract(layout.OffsetToHex(columns, 0), origin),
+		rowPeriod:    hex_subtract(layout.OffsetToHex(0, rows), origin),
+	}
+	t.topLeft, t.bottomRight = t.bounds()
+	return t, nil
+}
+
+func (t *wrapTopology) Layout() Layout {
+	return t.layout
+}
+
+func (t *wrapTopology) Columns() int {
+	return t.columns
+}
+
+func (t *wrapTopology) Rows() int {
+	return t.rows
+}
+
+func (t *wrapTopology) Hexes() []Hex {
+	return rectangle_shape(t.layout, t.columns, t.rows)
+}
+
+// wrap moves the hex into the world along the sides that wrap.
+func (t *wrapTopology) wrap(h Hex) Hex {
+	column, row := t.layout.HexToOffset(h)
+	if t.wrapColumns {
+		column = ((column % t.columns) + t.columns) % t.columns
+	}
+	if t.wrapRows {
+		row = ((row % t.rows) + t.rows) % t.rows
+	}
+	return t.layout.OffsetToHex(column, row)
+}
+
+func (t *wrapTopology) contains(h Hex) bool {
+	column, row := t.layout.HexToOffset(h)
+	return 0 <= column && column < t.columns && 0 <= row && row < t.rows
+}
+
+func (t *wrapTopology) Canonical(h Hex) (Hex, bool) {
+	h = t.wrap(h)
+	return h, t.contains(h)
+}
+
+func (t *wrapTopology) Neighbor(h Hex, direction Direction) (Hex, bool) {
+	return t.Canonical(hex_neighbor(h, direction.normalize()))
+}
+
+func (t *wrapTopology) Neighbors(h Hex) (results []Hex) {
+	for direction := 0; direction < 6; direction++ {
+		if neighbor, ok := t.Canonical(hex_neighbor(h, direction)); ok {
+			results = append(results, neighbor)
+		}
+	}
+	return results
+}
+
+// translates returns the periods to try when looking for the nearest copy of a hex.
+func (t *wrapTopology) translates() (results []Hex) {
+	columns, rows := []int{0}, []int{0}
+	if t.wrapColumns {
+		columns = []int{0, -1, 1}
+	}
+	if t.wrapRows {
+		rows = []int{0, -1, 1}
+	}
+	for _, i := range columns {
+		for _, j := range rows {
+			results = append(results, hex_add(hex_multiply(t.columnPeriod, i), hex_multiply(t.rowPeriod, j)))
+		}
+	}
+	return results
+}
+
+// nearest returns the copy of b that is closest to a.
+// Both hexes are wrapped first.
+func (t *wrapTopology) nearest(a, b Hex) (Hex, Hex) {
+	a, b = t.wrap(a), t.wrap(b)
+	best := b
+	for _, translate := range t.translates() {
+		if c := hex_add(b, translate); hex_distance(a, c) < hex_distance(a, best) {
+			best = c
+		}
+	}
+	return a, best
+}
+
+func (t *wrapTopology) Distance(a, b Hex) int {
+	a, b = t.nearest(a, b)
+	return hex_distance(a, b)
+}
+
+// LineDraw nudges the line to one side and then the other, because a line
+// that runs along the edge of a side that doesn't wrap can step off the world.
+func (t *wrapTopology) LineDraw(a, b Hex) (results []Hex) {
+	a, b = t.nearest(a, b)
+	for _, epsilon := range []float64{1e-6, -1e-6} {
+		results = hex_linedraw_nudged(a, b, epsilon)
+		inside := true
+		for i, h := range results {
+			results[i] = t.wrap(h)
+			inside = inside && t.contains(results[i])
+		}
+		if inside {
+			break
+		}
+	}
+	return results
+}
+
+func (t *wrapTopology) Bounds() (topLeft, bottomRight Point) {
+	topLeft = Point{X: math.Inf(1), Y: math.Inf(1)}
+	bottomRight = Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, pt := range [4]Point{t.topLeft, {X: t.bottomRight.X, Y: t.topLeft.Y}, t.bottomRight, {X: t.topLeft.X, Y: t.bottomRight.Y}} {
+		pt = t.camera.Apply(pt)
+		topLeft.X, topLeft.Y = min(topLeft.X, pt.X), min(topLeft.Y, pt.Y)
+		bottomRight.X, bottomRight.Y = max(bottomRight.X, pt.X), max(bottomRight.Y, pt.Y)
+	}
+	return topLeft, bottomRight
+}
+
+// bounds returns the rectangle covered by one copy of the world, before the camera.
+// The edge of the rectangle is half a step out from the hexes in the first row and
+// column that aren't pushed, so hexes that are pushed stick out the other side.
+func (t *wrapTopology) bounds() (topLeft, bottomRight Point) {
+	size := t.world.Size()
+	var width, height float64 // the distance between columns and rows
+	if t.layout.Orientation().IsFlat() {
+		width, height = 1.5*size.X, math.Sqrt(3)*size.Y
+	} else {
+		width, height = math.Sqrt(3)*size.X, 1.5*size.Y
+	}
+	topLeft = Point{X: math.Inf(1), Y: math.Inf(1)}
+	for column := 0; column < min(2, t.columns); column++ {
+		for row := 0; row < min(2, t.rows); row++ {
+			center := t.world.HexToCenterPoint(t.world.OffsetToHex(column, row))
+			topLeft.X = min(topLeft.X, center.X-width/2)
+			topLeft.Y = min(topLeft.Y, center.Y-height/2)
+		}
+	}
+	bottomRight = Point{X: topLeft.X + width*float64(t.columns), Y: topLeft.Y + height*float64(t.rows)}
+	return topLeft, bottomRight
+}
+
+func (t *wrapTopology) Images(h Hex) []Hex {
+	h = t.wrap(h)
+	results := []Hex{h}
+	for _, translate := range t.translates() {
+		if translate == (Hex{}) {
+			continue
+		}
+		image := hex_add(h, translate)
+		// the camera doesn't change which shapes overlap, so the test is done before it
+		if _, corners := t.world.Points(image); hex_overlaps_rect(corners, t.topLeft, t.bottomRight) {
+			results = append(results, image)
+		}
+	}
+	return results
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// test_topologies returns a cylinder and a torus for every layout.
+func test_topologies(t *testing.T, columns, rows int) map[string]Topology {
+	topologies := make(map[string]Topology)
+	for name, layout := range test_layouts() {
+		cylinder, err := NewCylinderTopology(layout, columns, rows)
+		if err != nil {
+			t.Fatalf("%s: cylinder: %v", name, err)
+		}
+		torus, err := NewTorusTopology(layout, columns, rows)
+		if err != nil {
+			t.Fatalf("%s: torus: %v", name, err)
+		}
+		topologies[name+" cylinder"], topologies[name+" torus"] = cylinder, torus
+	}
+	return topologies
+}
+
+// bfs_distances returns the number of steps from the hex to every hex in the world.
+func bfs_distances(topology Topology, from Hex) map[Hex]int {
+	distances := map[Hex]int{from: 0}
+	queue := []Hex{from}
+	for len(queue) != 0 {
+		h := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range topology.Neighbors(h) {
+			if _, ok := distances[neighbor]; !ok {
+				distances[neighbor] = distances[h] + 1
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	return distances
+}
+
+func TestTopologySize(t *testing.T) {
+	for _, tc := range []struct {
+		id            int
+		layout        Layout
+		columns, rows int
+		torus         bool
+		ok            bool
+	}{
+		{1, NewFlatEvenLayout(NewPoint(1, 1), NewPoint(0, 0)), 7, 6, false, false},
+		{2, NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0)), 7, 6, true, false},
+		{3, NewFlatOddLayout(NewPoint(1, 1), NewPoint(0, 0)), 8, 5, true, true},
+		{4, NewPointyEvenLayout(NewPoint(1, 1), NewPoint(0, 0)), 8, 5, true, false},
+		{5, NewPointyOddLayout(NewPoint(1, 1), NewPoint(0, 0)), 7, 5, false, true},
+		{6, NewPointyOddLayout(NewPoint(1, 1), NewPoint(0, 0)), 7, 6, true, true},
+		{7, NewFlatEvenLayout(NewPoint(1, 1), NewPoint(0, 0)), 0, 6, false, false},
+	} {
+		var err error
+		if tc.torus {
+			_, err = NewTorusTopology(tc.layout, tc.columns, tc.rows)
+		} else {
+			_, err = NewCylinderTopology(tc.layout, tc.columns, tc.rows)
+		}
+		if tc.ok && err != nil {
+			t.Errorf("%d: %d x %d: want no error: got %v", tc.id, tc.columns, tc.rows, err)
+		} else if !tc.ok && !errors.Is(err, ErrTopology) {
+			t.Errorf("%d: %d x %d: want %v: got %v", tc.id, tc.columns, tc.rows, ErrTopology, err)
+		}
+	}
+}
+
+func TestTopologyCanonical(t *testing.T) {
+	const columns, rows = 8, 6
+	for name, topology := range test_topologies(t, columns, rows) {
+		layout, torus := topology.Layout(), strings.HasSuffix(name, "torus")
+		for _, tc := range []struct {
+			id          int
+			column, row int
+			want        [2]int // the column and row in the world
+			wrapsRows   bool   // the hex is only in the world if the rows wrap
+		}{
+			{1, 3, 2, [2]int{3, 2}, false},
+			{2, -1, 2, [2]int{7, 2}, false},
+			{3, -8, 0, [2]int{0, 0}, false},
+			{4, -9, 5, [2]int{7, 5}, false},
+			{5, 8, 1, [2]int{0, 1}, false},
+			{6, 17, 4, [2]int{1, 4}, false},
+			{7, 2, -1, [2]int{2, 5}, true},
+			{8, 2, 6, [2]int{2, 0}, true},
+			{9, -1, -7, [2]int{7, 5}, true},
+			{10, 9, 13, [2]int{1, 1}, true},
+		} {
+			got, ok := topology.Canonical(layout.OffsetToHex(tc.column, tc.row))
+			if tc.wrapsRows && !torus {
+				if ok {
+					t.Errorf("%s: %d: Canonical(%d, %d): want off the world: got %v", name, tc.id, tc.column, tc.row, got)
+				}
+				continue
+			}
+			if want := layout.OffsetToHex(tc.want[0], tc.want[1]); !ok || got != want {
+				t.Errorf("%s: %d: Canonical(%d, %d): want %v: got %v, %v", name, tc.id, tc.column, tc.row, want, got, ok)
+			}
+		}
+	}
+}
+
+func TestTopologyNeighbor(t *testing.T) {
+	const columns, rows = 8, 6
+	for name, topology := range test_topologies(t, columns, rows) {
+		layout, torus := topology.Layout(), strings.HasSuffix(name, "torus")
+		for _, h := range topology.Hexes() {
+			for direction := Direction(0); direction < 6; direction++ {
+				got, ok := topology.Neighbor(h, direction)
+				_, row := layout.HexToOffset(h.Neighbor(direction))
+				if want := torus || (0 <= row && row < rows); ok != want {
+					t.Errorf("%s: %v: Neighbor(%d): want %v: got %v, %v", name, h, direction, want, got, ok)
+				} else if ok && got.DistanceTo(h) != 1 && topology.Distance(got, h) != 1 {
+					t.Errorf("%s: %v: Neighbor(%d): %v is not next to the hex", name, h, direction, got)
+				}
+			}
+		}
+	}
+}
+
+// TestTopologyDistance checks Distance against a breadth first search that
+// steps through Neighbors, so paths across the seams are counted.
+func TestTopologyDistance(t *testing.T) {
+	for name, topology := range test_topologies(t, 8, 6) {
+		world := topology.Hexes()
+		for _, a := range world {
+			distances := bfs_distances(topology, a)
+			for _, b := range world {
+				if got := topology.Distance(a, b); got != distances[b] {
+					t.Errorf("%s: Distance(%v, %v): want %d: got %d", name, a, b, distances[b], got)
+				}
+			}
+		}
+		// hexes outside the world are wrapped first, when the sides wrap
+		layout := topology.Layout()
+		if got := topology.Distance(layout.OffsetToHex(0, 2), layout.OffsetToHex(-1, 2)); got != 1 {
+			t.Errorf("%s: Distance across the seam: want 1: got %d", name, got)
+		}
+	}
+}
+
+func TestTopologyLineDraw(t *testing.T) {
+	for name, topology := range test_topologies(t, 8, 6) {
+		world := topology.Hexes()
+		for _, a := range world {
+			for _, b := range world {
+				line := topology.LineDraw(a, b)
+				if len(line) != topology.Distance(a, b)+1 || line[0] != a || line[len(line)-1] != b {
+					t.Fatalf("%s: LineDraw(%v, %v): got %v", name, a, b, line)
+				}
+				for i := 1; i < len(line); i++ {
+					if !slices.Contains(topology.Neighbors(line[i-1]), line[i]) {
+						t.Fatalf("%s: LineDraw(%v, %v): %v and %v aren't neighbors", name, a, b, line[i-1], line[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestTopologyImages checks that every point in the bounds is covered by an
+// image of the hex under it, so a renderer fills both sides of the seams.
+// A cylinder has gaps along the top and bottom, where the rows don't wrap.
+func TestTopologyImages(t *testing.T) {
+	for name, topology := range test_topologies(t, 8, 6) {
+		layout, torus := topology.Layout(), strings.HasSuffix(name, "torus")
+		for _, h := range topology.Hexes() {
+			if images := topology.Images(h); images[0] != h {
+				t.Errorf("%s: Images(%v): want the hex first: got %v", name, h, images)
+			}
+		}
+		topLeft, bottomRight := topology.Bounds()
+		for i := 1; i < 100; i++ {
+			for j := 1; j < 100; j++ {
+				p := NewPoint(lerp(topLeft.X, bottomRight.X, float64(i)/100), lerp(topLeft.Y, bottomRight.Y, float64(j)/100))
+				image := layout.PixelToHex(p)
+				h, ok := topology.Canonical(image)
+				if !ok && torus {
+					t.Fatalf("%s: %v: %v is off the world", name, p, image)
+				} else if !ok {
+					continue
+				}
+				if !slices.Contains(topology.Images(h), image) {
+					t.Errorf("%s: %v: want %v in Images(%v): got %v", name, p, image, h, topology.Images(h))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"math"
+	"slices"
+)
+
+// --------------------------------------------------------------------------------------------------------------------
+// viewport
+//
+// The queries in this section find the hexes under a shape on the screen.
+// They don't visit every hex on the map. The corners of the shape are
+// converted to fractional hexes, and only the hexes in the cube bounding
+// box of those corners are tested. Every hex that touches the shape is in
+// that box, because a point inside a hex is never more than 2/3 of a step
+// from its center in any cube coordinate.
+//
+// The shapes are in screen coordinates, after the camera, so the queries
+// work for every orientation and parity, for hexes that are wider than
+// they are tall, and for cameras that rotate the map.
+
+// HexesInRect returns the hexes that overlap the rectangle, sorted by r, then q.
+// Hexes that only touch the rectangle along an edge or at a corner don't overlap it.
+func HexesInRect(layout Layout, topLeft, bottomRight Point) (results []Hex) {
+	if bottomRight.X <= topLeft.X || bottomRight.Y <= topLeft.Y {
+		return nil
+	}
+	rect := [4]Point{topLeft, {X: bottomRight.X, Y: topLeft.Y}, bottomRight, {X: topLeft.X, Y: bottomRight.Y}}
+	bounds := cube_bounds_of(layout, rect[:], 1)
+	test := new_rect_test(layout, topLeft, bottomRight)
+	for r := bounds.MinR; r <= bounds.MaxR; r++ {
+		// s = -q - r, so the bounds on s are also bounds on q
+		qmin, qmax := max(bounds.MinQ, -bounds.MaxS-r), min(bounds.MaxQ, -bounds.MinS-r)
+		for q := qmin; q <= qmax; q++ {
+			h := Hex{q: q, r: r, s: -q - r}
+			if test.overlaps(layout.HexToCenterPoint(h)) {
+				results = append(results, h)
+			}
+		}
+	}
+	return results
+}
+
+// HexesInPolygon returns the hexes whose centers are inside the polygon, sorted by r, then q.
+// The polygon is closed and may be concave. If it crosses itself, a point is
+// inside when a ray from it crosses the outline an odd number of times.
+func HexesInPolygon(layout Layout, polygon []Point) (results []Hex) {
+	if len(polygon) < 3 {
+		return nil
+	}
+	bounds := cube_bounds_of(layout, polygon, 0)
+
+	// the centers of a row of hexes are evenly spaced along a line.
+	// each row is scanned like a scan line: find where the line crosses
+	// the outline and take the hexes between pairs of crossings.
+	var crossings []float64
+	for r := bounds.MinR; r <= bounds.MaxR; r++ {
+		origin := layout.HexToCenterPoint(Hex{q: 0, r: r, s: -r})
+		step := layout.HexToCenterPoint(Hex{q: 1, r: r, s: -1 - r}).Sub(origin)
+		crossings = crossings[:0]
+		for i, a := range polygon {
+			b := polygon[(i+1)%len(polygon)]
+			// a point on the line itself counts as being on the negative side,
+			// so a line through a corner of the polygon is counted correctly.
+			sa, sb := step.Cross(a.Sub(origin)), step.Cross(b.Sub(origin))
+			if (sa > 0) == (sb > 0) {
+				continue
+			}
+			x := a.Lerp(b, sa/(sa-sb))
+			crossings = append(crossings, x.Sub(origin).Dot(step)/step.Dot(step))
+		}
+		slices.Sort(crossings)
+		for i := 0; i+1 < len(crossings); i += 2 {
+			qmin := max(int(math.Ceil(crossings[i])), bounds.MinQ)
+			qmax := min(int(math.Floor(crossings[i+1])), bounds.MaxQ)
+			for q := qmin; q <= qmax; q++ {
+				results = append(results, Hex{q: q, r: r, s: -q - r})
+			}
+		}
+	}
+	return results
+}
+
+// cube_bounds_of returns the cube bounding box of the points, grown by margin steps.
+func cube_bounds_of(layout Layout, points []Point, margin float64) CubeBounds {
+	qmin, rmin, smin := math.Inf(1), math.Inf(1), math.Inf(1)
+	qmax, rmax, smax := math.Inf(-1), math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		f := layout.PixelToFractionalHex(p)
+		qmin, qmax = min(qmin, f.q), max(qmax, f.q)
+		rmin, rmax = min(rmin, f.r), max(rmax, f.r)
+		smin, smax = min(smin, f.s), max(smax, f.s)
+	}
+	return CubeBounds{
+		MinQ: int(math.Ceil(qmin - margin)), MaxQ: int(math.Floor(qmax + margin)),
+		MinR: int(math.Ceil(rmin - margin)), MaxR: int(math.Floor(rmax + margin)),
+		MinS: int(math.Ceil(smin - margin)), MaxS: int(math.Floor(smax + margin)),
+	}
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// separating axis test
+//
+// Two convex shapes don't overlap if there is an axis, normal to one of
+// their sides, where their projections don't overlap. A rectangle has two
+// axes and a hex has three, because opposite sides are parallel.
+//
+// Every hex in a layout is the same shape, so the axes and the projections
+// of the corners relative to the center are the same for every hex. Only
+// the projection of the center changes.
+
+// rect_test tests hexes against a rectangle.
+type rect_test struct {
+	axes             [5]Point
+	epsilon          [5]float64 // the tolerance on each axis, scaled by its length
+	hexMin, hexMax   [5]float64 // the projections of the corners, relative to the center
+	rectMin, rectMax [5]float64 // the projections of the rectangle
+}
+
+// new_rect_test returns a test for the hexes of the layout against the rectangle.
+func new_rect_test(layout Layout, topLeft, bottomRight Point) *rect_test {
+	center, corners := layout.Points(Hex{})
+	return new_rect_test_corners(center, corners, topLeft, bottomRight)
+}
+
+// new_rect_test_corners returns a test for hexes with the shape of the corners around the center.
+func new_rect_test_corners(center Point, corners [6]Point, topLeft, bottomRight Point) *rect_test {
+	const epsilon = 1e-9
+	rect := [4]Point{topLeft, {X: bottomRight.X, Y: topLeft.Y}, bottomRight, {X: topLeft.X, Y: bottomRight.Y}}
+	t := &rect_test{axes: [5]Point{{X: 1}, {Y: 1}}}
+	for i := 0; i < 3; i++ {
+		a, b := corners[i], corners[i+1]
+		t.axes[i+2] = Point{X: b.Y - a.Y, Y: a.X - b.X}
+	}
+	for i, axis := range t.axes {
+		t.epsilon[i] = epsilon * axis.Length()
+		t.hexMin[i], t.hexMax[i] = math.Inf(1), math.Inf(-1)
+		for _, pt := range corners {
+			d := pt.Sub(center).Dot(axis)
+			t.hexMin[i], t.hexMax[i] = min(t.hexMin[i], d), max(t.hexMax[i], d)
+		}
+		t.rectMin[i], t.rectMax[i] = math.Inf(1), math.Inf(-1)
+		for _, pt := range rect {
+			d := pt.Dot(axis)
+			t.rectMin[i], t.rectMax[i] = min(t.rectMin[i], d), max(t.rectMax[i], d)
+		}
+	}
+	return t
+}
+
+// overlaps returns true if the inside of the hex centered on the point overlaps the inside of the rectangle.
+// Shapes that only touch along an edge or at a corner don't overlap.
+func (t *rect_test) overlaps(center Point) bool {
+	for i, axis := range t.axes {
+		c := center.Dot(axis)
+		if c+t.hexMax[i] <= t.rectMin[i]+t.epsilon[i] || t.rectMax[i] <= c+t.hexMin[i]+t.epsilon[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hex_overlaps_rect returns true if the inside of the hex overlaps the inside of the rectangle.
+// Shapes that only touch along an edge or at a corner don't overlap.
+func hex_overlaps_rect(corners [6]Point, topLeft, bottomRight Point) bool {
+	center := corners[0].Lerp(corners[3], 0.5)
+	return new_rect_test_corners(center, corners, topLeft, bottomRight).overlaps(center)
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// test_viewport_layouts returns every layout and a layout seen through a camera that rotates and stretches it.
+func test_viewport_layouts(t *testing.T) map[string]Layout {
+	layouts := test_layouts()
+	camera := Identity().Scale(1.3, 0.8).RotateAround(NewPoint(20, 30), 0.45).Translate(7, -3)
+	rotated, err := NewCameraLayout(layouts["flat-odd"], camera)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layouts["rotated"] = rotated
+	return layouts
+}
+
+// brute_hexes returns the hexes within radius 40 of the origin that match, sorted by r, then q.
+func brute_hexes(match func(h Hex) bool) (results []Hex) {
+	const radius = 40
+	for r := -radius; r <= radius; r++ {
+		for q := max(-radius, -r-radius); q <= min(radius, -r+radius); q++ {
+			if h := NewHex(q, r, -q-r); match(h) {
+				results = append(results, h)
+			}
+		}
+	}
+	return results
+}
+
+// clipped_area returns the area of the part of the convex polygon inside the rectangle.
+// It clips the polygon against each side of the rectangle in turn.
+func clipped_area(polygon []Point, topLeft, bottomRight Point) float64 {
+	clip := func(in []Point, inside func(p Point) float64) (out []Point) {
+		for i, a := range in {
+			b := in[(i+1)%len(in)]
+			da, db := inside(a), inside(b)
+			if da >= 0 {
+				out = append(out, a)
+			}
+			if (da >= 0) != (db >= 0) {
+				out = append(out, a.Lerp(b, da/(da-db)))
+			}
+		}
+		return out
+	}
+	polygon = clip(polygon, func(p Point) float64 { return p.X - topLeft.X })
+	polygon = clip(polygon, func(p Point) float64 { return bottomRight.X - p.X })
+	polygon = clip(polygon, func(p Point) float64 { return p.Y - topLeft.Y })
+	polygon = clip(polygon, func(p Point) float64 { return bottomRight.Y - p.Y })
+	area := 0.0
+	for i, a := range polygon {
+		area += a.Cross(polygon[(i+1)%len(polygon)])
+	}
+	return math.Abs(area) / 2
+}
+
+// point_in_polygon returns true if the point is inside the polygon, using the even-odd rule.
+func point_in_polygon(p Point, polygon []Point) bool {
+	inside := false
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < a.X+(p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func TestHexesInRect(t *testing.T) {
+	for name, layout := range test_viewport_layouts(t) {
+		center, corners := layout.Points(Hex{})
+		hexArea := clipped_area(corners[:], NewPoint(-1e9, -1e9), NewPoint(1e9, 1e9))
+
+		var rects [][2]Point
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 200; i++ {
+			x, y := rng.Float64()*300-150, rng.Float64()*300-150
+			w, h := rng.Float64()*120, rng.Float64()*120
+			rects = append(rects, [2]Point{NewPoint(x, y), NewPoint(x+w, y+h)})
+		}
+
+		// rectangles that only touch the hex at the origin, at its extreme corners or along its sides.
+		xmin, ymin, xmax, ymax := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, pt := range corners {
+			xmin, ymin, xmax, ymax = min(xmin, pt.X), min(ymin, pt.Y), max(xmax, pt.X), max(ymax, pt.Y)
+		}
+		touching := [][2]Point{
+			{NewPoint(xmax, center.Y-5), NewPoint(xmax+40, center.Y+5)},
+			{NewPoint(xmin-40, center.Y-5), NewPoint(xmin, center.Y+5)},
+			{NewPoint(center.X-5, ymax), NewPoint(center.X+5, ymax+40)},
+			{NewPoint(center.X-5, ymin-40), NewPoint(center.X+5, ymin)},
+			{NewPoint(xmax, ymax), NewPoint(xmax+40, ymax+40)},
+		}
+		rects = append(rects, touching...)
+
+		for i, rect := range rects {
+			got := HexesInRect(layout, rect[0], rect[1])
+			want := brute_hexes(func(h Hex) bool {
+				_, corners := layout.Points(h)
+				return clipped_area(corners[:], rect[0], rect[1]) > 1e-9*hexArea
+			})
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: %d: %v-%v: want %v: got %v", name, i, rect[0], rect[1], want, got)
+			}
+		}
+		for i, rect := range touching {
+			if slices.Contains(HexesInRect(layout, rect[0], rect[1]), (Hex{})) {
+				t.Errorf("%s: touching %d: %v-%v: want the origin excluded", name, i, rect[0], rect[1])
+			}
+		}
+
+		if got := HexesInRect(layout, NewPoint(10, 10), NewPoint(10, 50)); got != nil {
+			t.Errorf("%s: empty rectangle: want nil: got %v", name, got)
+		}
+	}
+}
+
+func TestHexesInPolygon(t *testing.T) {
+	for name, layout := range test_viewport_layouts(t) {
+		// at returns the point at fractional axial coordinates, so the polygons
+		// can be placed relative to the rows of hex centers in every layout.
+		origin := layout.HexToCenterPoint(NewHex(0, 0, 0))
+		dq := layout.HexToCenterPoint(NewHex(1, 0, -1)).Sub(origin)
+		dr := layout.HexToCenterPoint(NewHex(0, 1, -1)).Sub(origin)
+		at := func(q, r float64) Point {
+			return origin.Add(dq.Scale(q)).Add(dr.Scale(r))
+		}
+
+		for i, polygon := range [][]Point{
+			// a triangle
+			{at(-3.3, -2.2), at(4.1, -1.7), at(0.2, 5.6)},
+			// a concave polygon with a vertex on row 0 and another on row 2 that
+			// point into the polygon, and a vertex on row 1 where the outline crosses the row.
+			{at(-4.3, -3.7), at(1.5, 0), at(5.2, -4.1), at(6.1, 4.4), at(0.5, 2), at(-3.9, 5.3), at(-4.5, 1)},
+			// a polygon that crosses itself
+			{at(-5.1, -5.2), at(5.3, 4.9), at(5.2, -5.3), at(-4.8, 5.1)},
+		} {
+			got := HexesInPolygon(layout, polygon)
+			want := brute_hexes(func(h Hex) bool {
+				return point_in_polygon(layout.HexToCenterPoint(h), polygon)
+			})
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: %d: want %v: got %v", name, i, want, got)
+			}
+		}
+
+		if got := HexesInPolygon(layout, []Point{origin, at(3, 0)}); got != nil {
+			t.Errorf("%s: two points: want nil: got %v", name, got)
+		}
+	}
+}
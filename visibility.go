@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+// --------------------------------------------------------------------------------------------------------------------
+// line of sight is from https://www.redblobgames.com/grids/hexagons/#line-drawing
+//
+// A line between two hex centers can run exactly along the edge between two hexes.
+// We draw the line twice, nudged to either side of the edge, and the view is clear
+// if either line is clear. The end points never block the view.
+
+// LineOfSight returns true if no hex between a and b blocks the view.
+func LineOfSight(a, b Hex, blocks func(h Hex) bool) bool {
+	for _, epsilon := range []float64{1e-6, -1e-6} {
+		line, clear := hex_linedraw_nudged(a, b, epsilon), true
+		for i := 1; clear && i < len(line)-1; i++ {
+			clear = !blocks(line[i])
+		}
+		if clear {
+			return true
+		}
+	}
+	return false
+}
+
+// --------------------------------------------------------------------------------------------------------------------
+// field of view
+//
+// The field of view casts a line of sight from the viewer to every hex in range.
+// The line starts at the viewer's eye (ground elevation plus EyeHeight) and ends
+// at the target (ground elevation plus TargetHeight). Every hex between them is
+// checked against the height of the line where it crosses that hex:
+//   - if the ground is above the line, the view is blocked
+//   - if the top of the obstruction (trees, buildings) is above the line,
+//     the obstruction's opacity is added to the total
+// The target is visible when the total opacity along either nudged line is less than 1.
+// That means two hexes of woods with an opacity of 0.5 block the view but one does not.
+//
+// This isn't shadowcasting. Shadows from partial opacity add up along each line
+// and depend on the heights at both ends, so they can't be swept out as arcs.
+// Casting a line to every hex costs O(R³), which BenchmarkFieldOfView puts at
+// about 1.5ms for a radius of 20 and 11ms for a radius of 40. That is cheap for a
+// turn based game, and FieldOfView always agrees with CanSee.
+
+// Sight describes how a hex affects visibility.
+type Sight struct {
+	Elevation float64 // height of the ground
+	Height    float64 // height of the obstruction above the ground
+	Opacity   float64 // 0 is clear, 1 blocks the view completely
+}
+
+// FOVOptions configures the field of view.
+type FOVOptions struct {
+	// Radius is the maximum number of hexes the viewer can see.
+	Radius int
+
+	// EyeHeight is the height of the viewer above the ground.
+	EyeHeight float64
+
+	// TargetHeight is the height above the ground of the thing being looked at.
+	// Zero means the viewer must see the ground itself.
+	TargetHeight float64
+
+	// Sight returns the terrain for the hex.
+	// If nil, every hex is flat and clear.
+	Sight func(h Hex) Sight
+}
+
+func (opts FOVOptions) sight(h Hex) Sight {
+	if opts.Sight == nil {
+		return Sight{}
+	}
+	return opts.Sight(h)
+}
+
+// CanSee returns true if a viewer in hex a can see hex b.
+// The radius in the options is ignored.
+func CanSee(a, b Hex, opts FOVOptions) bool {
+	if a == b {
+		return true
+	}
+	eye := opts.sight(a).Elevation + opts.EyeHeight
+	target := opts.sight(b).Elevation + opts.TargetHeight
+	for _, epsilon := range []float64{1e-6, -1e-6} {
+		line := hex_linedraw_nudged(a, b, epsilon)
+		if sight_line_opacity(line, eye, target, opts) < 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// sight_line_opacity returns the total opacity of the hexes between the end points of the line.
+func sight_line_opacity(line []Hex, eye, target float64, opts FOVOptions) (opacity float64) {
+	N := len(line) - 1
+	for i := 1; i < N; i++ {
+		height := lerp(eye, target, float64(i)/float64(N))
+		sight := opts.sight(line[i])
+		if sight.Elevation > height {
+			return 1
+		} else if sight.Elevation+sight.Height > height {
+			opacity += sight.Opacity
+			if opacity >= 1 {
+				return opacity
+			}
+		}
+	}
+	return opacity
+}
+
+// FieldOfView returns the hexes within the radius that can be seen from the origin.
+// The origin is always visible.
+func FieldOfView(origin Hex, opts FOVOptions) (visible []Hex) {
+	for _, offset := range hexagon_shape(opts.Radius) {
+		if h := hex_add(origin, offset); CanSee(origin, h, opts) {
+			visible = append(visible, h)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Michael D Henderson. All rights reserved.
+
+package hexes
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestLineOfSight(t *testing.T) {
+	// the line from the origin to (2, -1, -1) runs along the edge between (1, 0, -1) and (1, -1, 0).
+	left, right := NewHex(1, 0, -1), NewHex(1, -1, 0)
+	for _, tc := range []struct {
+		id      int
+		a, b    Hex
+		blocked []Hex
+		want    bool
+	}{
+		{1, NewHex(0, 0, 0), NewHex(3, 0, -3), nil, true},
+		{2, NewHex(0, 0, 0), NewHex(3, 0, -3), []Hex{NewHex(2, 0, -2)}, false},
+		{3, NewHex(0, 0, 0), NewHex(2, -1, -1), []Hex{left}, true},
+		{4, NewHex(0, 0, 0), NewHex(2, -1, -1), []Hex{right}, true},
+		{5, NewHex(0, 0, 0), NewHex(2, -1, -1), []Hex{left, right}, false},
+		{6, NewHex(0, 0, 0), NewHex(1, 0, -1), []Hex{NewHex(0, 0, 0), NewHex(1, 0, -1)}, true}, // the end points never block
+		{7, NewHex(0, 0, 0), NewHex(0, 0, 0), []Hex{NewHex(0, 0, 0)}, true},
+	} {
+		blocks := func(h Hex) bool { return slices.Contains(tc.blocked, h) }
+		if got := LineOfSight(tc.a, tc.b, blocks); got != tc.want {
+			t.Errorf("%d: LineOfSight(%v, %v): want %v: got %v", tc.id, tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestCanSee(t *testing.T) {
+	origin, target := NewHex(0, 0, 0), NewHex(4, 0, -4)
+	hill := func(elevation float64) func(h Hex) Sight {
+		return func(h Hex) Sight {
+			switch h {
+			case NewHex(2, 0, -2):
+				return Sight{Elevation: 10}
+			case origin:
+				return Sight{Elevation: elevation}
+			}
+			return Sight{}
+		}
+	}
+	woods := func(hv ...Hex) func(h Hex) Sight {
+		return func(h Hex) Sight {
+			if slices.Contains(hv, h) {
+				return Sight{Height: 5, Opacity: 0.5}
+			}
+			return Sight{}
+		}
+	}
+	for _, tc := range []struct {
+		id   int
+		b    Hex
+		opts FOVOptions
+		want bool
+	}{
+		{1, target, FOVOptions{}, true},
+		{2, target, FOVOptions{EyeHeight: 1, Sight: hill(0)}, false}, // the hill is above the line
+		{3, target, FOVOptions{EyeHeight: 1, Sight: hill(30)}, true}, // the viewer looks over the hill
+		{4, target, FOVOptions{EyeHeight: 1, TargetHeight: 30, Sight: hill(0)}, true},
+		{5, target, FOVOptions{EyeHeight: 1, Sight: woods(NewHex(1, 0, -1))}, true},
+		{6, target, FOVOptions{EyeHeight: 1, Sight: woods(NewHex(1, 0, -1), NewHex(3, 0, -3))}, false},
+		{7, target, FOVOptions{EyeHeight: 10, Sight: woods(NewHex(1, 0, -1), NewHex(3, 0, -3))}, true}, // over the trees
+		{8, origin, FOVOptions{Sight: func(h Hex) Sight { return Sight{Elevation: 100, Height: 100, Opacity: 1} }}, true},
+		// the line to (2, -1, -1) runs along an edge, so one tree on either side doesn't block it
+		{9, NewHex(2, -1, -1), FOVOptions{Sight: woods(NewHex(1, 0, -1))}, true},
+		{10, NewHex(2, -1, -1), FOVOptions{Sight: func(h Hex) Sight {
+			if h == NewHex(1, -1, 0) {
+				return Sight{Elevation: 1}
+			}
+			return Sight{}
+		}}, true},
+		{11, NewHex(2, -1, -1), FOVOptions{Sight: func(h Hex) Sight {
+			if h == NewHex(1, -1, 0) || h == NewHex(1, 0, -1) {
+				return Sight{Elevation: 1}
+			}
+			return Sight{}
+		}}, false},
+	} {
+		if got := CanSee(origin, tc.b, tc.opts); got != tc.want {
+			t.Errorf("%d: CanSee(%v, %v): want %v: got %v", tc.id, origin, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestFieldOfView(t *testing.T) {
+	origin := NewHex(2, -1, -1)
+	if got := FieldOfView(origin, FOVOptions{Radius: 4}); len(got) != 3*4*4+3*4+1 {
+		t.Errorf("open ground: want %d hexes: got %d", 3*4*4+3*4+1, len(got))
+	}
+
+	// the viewer stands in a hex of dense woods and sees out of it,
+	// but a wall to the east hides the hexes behind it.
+	wall := []Hex{origin.Add(NewHex(2, -1, -1)), origin.Add(NewHex(2, 0, -2)), origin.Add(NewHex(2, -2, 0))}
+	opts := FOVOptions{Radius: 4, Sight: func(h Hex) Sight {
+		if h == origin || slices.Contains(wall, h) {
+			return Sight{Height: 10, Opacity: 1}
+		}
+		return Sight{}
+	}}
+	got := FieldOfView(origin, opts)
+	if !slices.Contains(got, origin) {
+		t.Errorf("want the viewer's own hex")
+	}
+	for _, h := range wall {
+		if !slices.Contains(got, h) {
+			t.Errorf("want the wall at %v", h)
+		}
+	}
+	if behind := origin.Add(NewHex(3, 0, -3)); slices.Contains(got, behind) {
+		t.Errorf("want %v hidden by the wall", behind)
+	}
+	if beside := origin.Add(NewHex(0, 3, -3)); !slices.Contains(got, beside) {
+		t.Errorf("want %v visible", beside)
+	}
+	for _, h := range got {
+		if !CanSee(origin, h, opts) {
+			t.Errorf("%v: in the field of view but CanSee is false", h)
+		}
+	}
+}
+
+func BenchmarkFieldOfView(b *testing.B) {
+	opts := FOVOptions{EyeHeight: 2, Sight: func(h Hex) Sight {
+		if (h.q*7+h.r*13)%5 == 0 {
+			return Sight{Height: 5, Opacity: 0.5}
+		}
+		return Sight{Elevation: float64((h.q * h.r) % 3)}
+	}}
+	for _, radius := range []int{5, 10, 20, 40} {
+		b.Run(fmt.Sprintf("radius %d", radius), func(b *testing.B) {
+			opts.Radius = radius
+			for i := 0; i < b.N; i++ {
+				FieldOfView(NewHex(0, 0, 0), opts)
+			}
+		})
+	}
+}